@@ -14,6 +14,7 @@ import (
 	"github.com/baolamabcd13/datahiding-text-app/internal/email"
 	"github.com/baolamabcd13/datahiding-text-app/internal/middleware"
 	"github.com/baolamabcd13/datahiding-text-app/internal/models"
+	"github.com/baolamabcd13/datahiding-text-app/internal/stego"
 	"github.com/baolamabcd13/datahiding-text-app/internal/tasks"
 	"github.com/baolamabcd13/datahiding-text-app/internal/user"
 	"github.com/baolamabcd13/datahiding-text-app/internal/validation"
@@ -71,13 +72,52 @@ func main() {
 		EmailVerificationRequired: cfg.EmailVerificationRequired,
 	}
 
+	// Khởi tạo stego config
+	stegoConfig := stego.Config{
+		KDF: stego.KDFConfig{
+			Time:     uint32(cfg.StegoArgon2Time),
+			MemoryKB: uint32(cfg.StegoArgon2MemoryKB),
+			Threads:  uint8(cfg.StegoArgon2Threads),
+		},
+		Homoglyph: stego.HomoglyphConfig{
+			Table:   cfg.StegoHomoglyphTable,
+			Exclude: cfg.StegoHomoglyphExclude,
+		},
+	}
+	for _, name := range cfg.StegoVSStrippingPlatforms {
+		stegoConfig.Platforms = append(stegoConfig.Platforms, stego.PlatformInfo{
+			Name:                     name,
+			StripsVariationSelectors: true,
+		})
+	}
+	for name, form := range cfg.StegoNormalizingPlatforms {
+		stegoConfig.Platforms = append(stegoConfig.Platforms, stego.PlatformInfo{
+			Name:          name,
+			Normalization: form,
+		})
+	}
+	stegoConfig.Synonyms, err = stego.LoadSynonymLibrary(cfg.StegoSynonymDir)
+	if err != nil {
+		log.Fatalf("Failed to load synonym dictionaries: %v", err)
+	}
+	stegoConfig.Ngrams, err = stego.LoadNgramLibrary(cfg.StegoNgramDir)
+	if err != nil {
+		log.Fatalf("Failed to load n-gram models: %v", err)
+	}
+	stegoRegistry, err := stego.NewDefaultRegistry(stegoConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize stego methods: %v", err)
+	}
+
 	// Khởi tạo services
 	authService := auth.NewAuthService(authRepo, cfg.JWTSecret, emailService, authConfig, tokenRepo)
 	userService := user.NewUserService(userRepo)
+	stegoService := stego.NewStegoService(stegoRegistry, stegoConfig)
 
 	// Khởi tạo handlers
 	authHandler := auth.NewHandler(authService)
 	userHandler := user.NewHandler(userService)
+	stegoHandler := stego.NewHandler(stegoService)
 
 	// Khởi tạo middleware
 	authMiddleware := middleware.AuthMiddleware(cfg.JWTSecret, tokenRepo)
@@ -105,6 +145,7 @@ func main() {
 	api := router.Group("/api")
 	authHandler.SetupRoutes(api)
 	userHandler.SetupRoutes(api, authMiddleware)
+	stegoHandler.SetupRoutes(api, authMiddleware)
 
 	// Lên lịch xóa token hết hạn (chạy mỗi 24 giờ)
 	tasks.ScheduleTokenCleanup(db, 24*time.Hour)
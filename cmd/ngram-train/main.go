@@ -0,0 +1,65 @@
+// Lệnh ngram-train - Huấn luyện mô hình n-gram từ corpus để sinh văn bản phủ.
+//
+// Cách dùng:
+//
+//	ngram-train -o vi.ngram [-order 3] [-top-k 16] corpus1.txt corpus2.txt ...
+//
+// Mỗi dòng của corpus là một đoạn văn; không có file nào thì đọc từ stdin.
+// Đặt file <ngôn ngữ>.ngram vào thư mục STEGO_NGRAM_DIR để server sử dụng.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/stego"
+)
+
+func main() {
+	output := flag.String("o", "", "đường dẫn file mô hình đầu ra (bắt buộc)")
+	order := flag.Int("order", stego.DefaultNgramOrder, "bậc của mô hình (số token kể cả token được dự đoán)")
+	topK := flag.Int("top-k", stego.DefaultNgramTopK, "số ứng viên giữ lại cho mỗi ngữ cảnh")
+	flag.Parse()
+
+	if *output == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Thêm dòng trống giữa các file để đoạn cuối của file trước không nối với đoạn đầu file sau
+	var readers []io.Reader
+	for _, name := range flag.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			log.Fatalf("Failed to open corpus: %v", err)
+		}
+		defer file.Close()
+		readers = append(readers, file, strings.NewReader("\n"))
+	}
+	if len(readers) == 0 {
+		readers = append(readers, os.Stdin)
+	}
+
+	model, err := stego.TrainNgramModel(io.MultiReader(readers...), *order, *topK)
+	if err != nil {
+		log.Fatalf("Failed to train model: %v", err)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		log.Fatalf("Failed to create model file: %v", err)
+	}
+	size, err := model.WriteTo(file)
+	if err != nil {
+		log.Fatalf("Failed to write model: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		log.Fatalf("Failed to write model: %v", err)
+	}
+	info := model.Info()
+	fmt.Printf("Wrote %s: order %d, %d tokens, %d contexts, %d bytes\n", *output, info.Order, info.Vocabulary, info.Contexts, size)
+}
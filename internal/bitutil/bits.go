@@ -0,0 +1,44 @@
+// Package bitutil chứa các hàm xử lý bit và hoán vị dùng chung cho các phương pháp giấu tin.
+// Bit được biểu diễn bằng một byte có giá trị 0 hoặc 1, bit cao của mỗi byte đứng trước.
+package bitutil
+
+// BytesToBits - Tách dữ liệu thành các bit (0 hoặc 1), bit cao trước
+func BytesToBits(data []byte) []byte {
+	bits := make([]byte, 0, len(data)*8)
+	for _, b := range data {
+		for shift := 7; shift >= 0; shift-- {
+			bits = append(bits, (b>>uint(shift))&1)
+		}
+	}
+	return bits
+}
+
+// BitsToBytes - Gộp các bit thành byte, bỏ qua các bit lẻ cuối cùng
+func BitsToBytes(bits []byte) []byte {
+	data := make([]byte, len(bits)/8)
+	for i := range data {
+		var b byte
+		for _, bit := range bits[i*8 : i*8+8] {
+			b = b<<1 | bit&1
+		}
+		data[i] = b
+	}
+	return data
+}
+
+// AppendUintBits - Nối n bit thấp của value vào bits, bit cao trước
+func AppendUintBits(bits []byte, value uint64, n int) []byte {
+	for shift := n - 1; shift >= 0; shift-- {
+		bits = append(bits, byte(value>>uint(shift))&1)
+	}
+	return bits
+}
+
+// UintFromBits - Gộp các bit (bit cao trước) thành số nguyên
+func UintFromBits(bits []byte) uint64 {
+	var value uint64
+	for _, bit := range bits {
+		value = value<<1 | uint64(bit&1)
+	}
+	return value
+}
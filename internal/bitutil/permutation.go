@@ -0,0 +1,57 @@
+package bitutil
+
+import "math/bits"
+
+// MaxPermutationItems - Số phần tử tối đa được hoán vị: 20! < 2^63 nên chỉ số hoán vị
+// vừa trong uint64
+const MaxPermutationItems = 20
+
+// PermutationBits - Số bit mang được bởi thứ tự của n phần tử, floor(log2(n!)).
+// Trả về 0 nếu n vượt quá MaxPermutationItems.
+func PermutationBits(n int) int {
+	if n < 2 || n > MaxPermutationItems {
+		return 0
+	}
+	f := uint64(1)
+	for i := 2; i <= n; i++ {
+		f *= uint64(i)
+	}
+	return bits.Len64(f) - 1
+}
+
+// PermutationIndex - Chỉ số (mã Lehmer) của hoán vị perm trên các phần tử 0..n-1,
+// hoán vị đồng nhất có chỉ số 0
+func PermutationIndex(perm []int) uint64 {
+	n := len(perm)
+	var index uint64
+	for i, p := range perm {
+		smaller := 0
+		for _, q := range perm[i+1:] {
+			if q < p {
+				smaller++
+			}
+		}
+		index = index*uint64(n-i) + uint64(smaller)
+	}
+	return index
+}
+
+// PermutationFromIndex - Hoán vị của n phần tử có chỉ số index, ngược với PermutationIndex
+func PermutationFromIndex(n int, index uint64) []int {
+	digits := make([]int, n)
+	for i := n - 1; i >= 0; i-- {
+		base := uint64(n - i)
+		digits[i] = int(index % base)
+		index /= base
+	}
+	remaining := make([]int, n)
+	for i := range remaining {
+		remaining[i] = i
+	}
+	perm := make([]int, n)
+	for i, d := range digits {
+		perm[i] = remaining[d]
+		remaining = append(remaining[:d], remaining[d+1:]...)
+	}
+	return perm
+}
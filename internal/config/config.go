@@ -24,6 +24,15 @@ type Config struct {
 	EmailPort               int
 	AppURL                  string
 	CORSAllowOrigins        []string
+	StegoArgon2Time         int
+	StegoArgon2MemoryKB     int
+	StegoArgon2Threads      int
+	StegoHomoglyphTable     string
+	StegoHomoglyphExclude   string
+	StegoVSStrippingPlatforms []string
+	StegoNormalizingPlatforms map[string]string
+	StegoSynonymDir         string
+	StegoNgramDir           string
 }
 
 // LoadConfig - Tải cấu hình từ file .env
@@ -74,6 +83,55 @@ func LoadConfig() *Config {
 	corsAllowOriginsStr := getEnv("CORS_ALLOW_ORIGINS", "http://localhost:3000")
 	corsAllowOrigins := strings.Split(corsAllowOriginsStr, ",")
 
+	// Đọc cấu hình chi phí Argon2id cho mã hóa dữ liệu giấu
+	stegoArgon2Time, err := strconv.Atoi(getEnv("STEGO_ARGON2_TIME", "3"))
+	if err != nil || stegoArgon2Time < 1 || stegoArgon2Time > 255 {
+		log.Printf("Warning: Invalid STEGO_ARGON2_TIME, using default value: %v", err)
+		stegoArgon2Time = 3
+	}
+
+	stegoArgon2MemoryKB, err := strconv.Atoi(getEnv("STEGO_ARGON2_MEMORY_KB", "65536"))
+	if err != nil || stegoArgon2MemoryKB < 8 {
+		log.Printf("Warning: Invalid STEGO_ARGON2_MEMORY_KB, using default value: %v", err)
+		stegoArgon2MemoryKB = 65536
+	}
+
+	stegoArgon2Threads, err := strconv.Atoi(getEnv("STEGO_ARGON2_THREADS", "4"))
+	if err != nil || stegoArgon2Threads < 1 || stegoArgon2Threads > 255 {
+		log.Printf("Warning: Invalid STEGO_ARGON2_THREADS, using default value: %v", err)
+		stegoArgon2Threads = 4
+	}
+
+	// Đọc cấu hình bảng ký tự đồng dạng (danh sách ký tự loại trừ viết liền, ví dụ "ijsJS")
+	stegoHomoglyphTable := getEnv("STEGO_HOMOGLYPH_TABLE", "v1")
+	stegoHomoglyphExclude := getEnv("STEGO_HOMOGLYPH_EXCLUDE", "")
+
+	// Đọc danh sách nền tảng xóa variation selector (bổ sung cho bảng có sẵn)
+	var stegoVSStrippingPlatforms []string
+	if platforms := getEnv("STEGO_VS_STRIPPING_PLATFORMS", ""); platforms != "" {
+		stegoVSStrippingPlatforms = strings.Split(platforms, ",")
+	}
+
+	// Đọc danh sách nền tảng chuẩn hóa Unicode, dạng "tên=NFC,tên=NFD"
+	stegoNormalizingPlatforms := make(map[string]string)
+	if platforms := getEnv("STEGO_NORMALIZING_PLATFORMS", ""); platforms != "" {
+		for _, entry := range strings.Split(platforms, ",") {
+			name, form, ok := strings.Cut(entry, "=")
+			form = strings.ToUpper(strings.TrimSpace(form))
+			if !ok || strings.TrimSpace(name) == "" || (form != "NFC" && form != "NFD") {
+				log.Printf("Warning: Invalid STEGO_NORMALIZING_PLATFORMS entry %q, skipping", entry)
+				continue
+			}
+			stegoNormalizingPlatforms[strings.TrimSpace(name)] = form
+		}
+	}
+
+	// Đọc thư mục từ điển đồng nghĩa tùy chỉnh (các file <ngôn ngữ>.txt)
+	stegoSynonymDir := getEnv("STEGO_SYNONYM_DIR", "")
+
+	// Đọc thư mục mô hình n-gram (các file <ngôn ngữ>.ngram tạo bằng lệnh ngram-train)
+	stegoNgramDir := getEnv("STEGO_NGRAM_DIR", "")
+
 	return &Config{
 		DBHost:                  dbHost,
 		DBPort:                  dbPort,
@@ -87,6 +145,15 @@ func LoadConfig() *Config {
 		EmailPort:               emailPort,
 		AppURL:                  appURL,
 		CORSAllowOrigins:        corsAllowOrigins,
+		StegoArgon2Time:         stegoArgon2Time,
+		StegoArgon2MemoryKB:     stegoArgon2MemoryKB,
+		StegoArgon2Threads:      stegoArgon2Threads,
+		StegoHomoglyphTable:     stegoHomoglyphTable,
+		StegoHomoglyphExclude:   stegoHomoglyphExclude,
+		StegoVSStrippingPlatforms: stegoVSStrippingPlatforms,
+		StegoNormalizingPlatforms: stegoNormalizingPlatforms,
+		StegoSynonymDir:         stegoSynonymDir,
+		StegoNgramDir:           stegoNgramDir,
 	}
 }
 
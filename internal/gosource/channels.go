@@ -0,0 +1,410 @@
+package gosource
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+)
+
+// Thứ tự các loại vị trí khi hai vị trí có cùng điểm neo trong mã nguồn
+const (
+	slotImports = iota
+	slotComment
+	slotDeclaration
+	slotOperands
+)
+
+// slot - Một vị trí mang tin: điểm neo để sắp thứ tự, số bit, giá trị hiện tại và hàm tạo
+// các thay đổi để vị trí mang giá trị mới
+type slot struct {
+	pos   token.Pos
+	kind  int
+	bits  int
+	value uint64
+	write func(value uint64) []edit
+}
+
+// slots - Các vị trí mang tin theo thứ tự trong mã nguồn. Điểm neo của mỗi vị trí không
+// đổi thứ tự khi các vị trí khác được viết lại.
+func (e *Encoder) slots(f *file) []slot {
+	var slots []slot
+	if e.Channels.Imports {
+		slots = append(slots, f.importSlots()...)
+	}
+	if e.Channels.Operands {
+		slots = append(slots, f.operandSlots()...)
+	}
+	if e.Channels.Declarations || e.Channels.Comments {
+		for _, list := range f.statementLists() {
+			for _, stmt := range list {
+				if e.Channels.Declarations {
+					if s, ok := f.declarationSlot(stmt); ok {
+						slots = append(slots, s)
+					}
+				}
+				if e.Channels.Comments {
+					if s, ok := f.commentSlot(stmt); ok {
+						slots = append(slots, s)
+					}
+				}
+			}
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].pos != slots[j].pos {
+			return slots[i].pos < slots[j].pos
+		}
+		return slots[i].kind < slots[j].kind
+	})
+	return slots
+}
+
+// importSlots - Thứ tự các nhóm trong khai báo import có ngoặc đầu tiên. Thứ tự khởi tạo
+// các gói không phụ thuộc vào thứ tự import nên các nhóm đổi chỗ được cho nhau; gofmt chỉ
+// sắp xếp bên trong từng nhóm.
+func (f *file) importSlots() []slot {
+	for _, decl := range f.ast.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT || !gd.Lparen.IsValid() {
+			continue
+		}
+		start, end := f.offset(gd.Lparen)+1, f.offset(gd.Rparen)
+		groups := strings.Split(strings.Trim(string(f.src[start:end]), "\n"), "\n\n")
+		n := bitutil.PermutationBits(len(groups))
+		if n == 0 {
+			return nil
+		}
+		sorted := append([]string(nil), groups...)
+		sort.Strings(sorted)
+		rank := make(map[string]int, len(sorted))
+		for i, group := range sorted {
+			// Nhóm trùng nhau hoặc chỉ gồm chú thích thì không xác định được thứ tự
+			if _, dup := rank[group]; dup || !hasImportSpec(group) {
+				return nil
+			}
+			rank[group] = i
+		}
+		order := make([]int, len(groups))
+		for i, group := range groups {
+			order[i] = rank[group]
+		}
+		return []slot{{
+			pos:   gd.Lparen,
+			kind:  slotImports,
+			bits:  n,
+			value: bitutil.PermutationIndex(order),
+			write: func(value uint64) []edit {
+				perm := bitutil.PermutationFromIndex(len(sorted), value)
+				reordered := make([]string, len(perm))
+				for i, p := range perm {
+					reordered[i] = sorted[p]
+				}
+				return []edit{{start, end, "\n" + strings.Join(reordered, "\n\n") + "\n"}}
+			},
+		}}
+	}
+	return nil
+}
+
+// hasImportSpec - Nhóm import có ít nhất một dòng không phải chú thích và không chứa
+// chú thích khối (có thể trải qua dòng trống)
+func hasImportSpec(group string) bool {
+	if strings.Contains(group, "/*") {
+		return false
+	}
+	for _, line := range strings.Split(group, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "//") {
+			return true
+		}
+	}
+	return false
+}
+
+// commutativeOps - Các phép toán đổi chỗ toán hạng được, cùng điều kiện về kiểu toán hạng.
+// Phép + trên chuỗi không giao hoán, && và || có thể bỏ qua vế phải nên không được dùng.
+var commutativeOps = map[token.Token]types.BasicInfo{
+	token.ADD: types.IsNumeric,
+	token.MUL: types.IsNumeric,
+	token.AND: types.IsInteger,
+	token.OR:  types.IsInteger,
+	token.XOR: types.IsInteger,
+	token.EQL: 0,
+	token.NEQ: 0,
+}
+
+// operandSlots - Các phép toán giao hoán có hai toán hạng là tên biến hoặc hằng số.
+// Đọc biến hay hằng không có tác dụng phụ nên thứ tự tính toán không ảnh hưởng chương trình.
+// Thứ tự chuẩn (0) là toán hạng có mã nguồn nhỏ hơn đứng trước.
+func (f *file) operandSlots() []slot {
+	info := f.typeInfo()
+	var slots []slot
+	ast.Inspect(f.ast, func(n ast.Node) bool {
+		expr, ok := n.(*ast.BinaryExpr)
+		if !ok {
+			return true
+		}
+		need, ok := commutativeOps[expr.Op]
+		if !ok || !plainOperand(expr.X) || !plainOperand(expr.Y) {
+			return true
+		}
+		for _, operand := range []ast.Expr{expr.X, expr.Y} {
+			tv, ok := info.Types[operand]
+			if !ok || tv.Type == nil || tv.Type == types.Typ[types.Invalid] {
+				return true
+			}
+			if need != 0 {
+				basic, ok := tv.Type.Underlying().(*types.Basic)
+				if !ok || basic.Info()&need == 0 {
+					return true
+				}
+			}
+		}
+		if tv, ok := info.Types[expr]; !ok || tv.Type == nil || tv.Type == types.Typ[types.Invalid] {
+			return true
+		}
+
+		x, y := f.text(expr.X), f.text(expr.Y)
+		if x == y {
+			return true
+		}
+		var value uint64
+		if x > y {
+			value = 1
+		}
+		xs, xe := f.offset(expr.X.Pos()), f.offset(expr.X.End())
+		ys, ye := f.offset(expr.Y.Pos()), f.offset(expr.Y.End())
+		slots = append(slots, slot{
+			pos:   expr.OpPos,
+			kind:  slotOperands,
+			bits:  1,
+			value: value,
+			write: func(uint64) []edit {
+				return []edit{{xs, xe, y}, {ys, ye, x}}
+			},
+		})
+		return true
+	})
+	return slots
+}
+
+// plainOperand - Tên (trừ nil và _) hoặc hằng số viết trực tiếp
+func plainOperand(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name != "nil" && expr.Name != "_"
+	case *ast.BasicLit:
+		return true
+	}
+	return false
+}
+
+// stubImporter - Không nạp gói nào; biểu thức dùng gói ngoài có kiểu không hợp lệ và bị bỏ qua
+type stubImporter struct{}
+
+func (stubImporter) Import(path string) (*types.Package, error) {
+	return nil, fmt.Errorf("package %s is not available", path)
+}
+
+// typeInfo - Kiểu của các biểu thức và các tên được khai báo, lỗi kiểm tra kiểu được bỏ qua
+func (f *file) typeInfo() *types.Info {
+	if f.info != nil {
+		return f.info
+	}
+	f.info = &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: stubImporter{}, Error: func(error) {}}
+	_, _ = conf.Check(f.ast.Name.Name, f.fset, []*ast.File{f.ast}, f.info)
+	return f.info
+}
+
+// statementLists - Các danh sách câu lệnh trong thân hàm
+func (f *file) statementLists() [][]ast.Stmt {
+	var lists [][]ast.Stmt
+	ast.Inspect(f.ast, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			lists = append(lists, n.List)
+		case *ast.CaseClause:
+			lists = append(lists, n.Body)
+		case *ast.CommClause:
+			lists = append(lists, n.Body)
+		}
+		return true
+	})
+	return lists
+}
+
+// declarationSlot - Khai báo biến không ghi kiểu: a, b := v (0) hoặc var a, b = v (1).
+// Câu lệnh đứng trực tiếp trong danh sách câu lệnh nên var được dùng ở đó; := chỉ được đổi
+// khi mọi tên khác _ đều là biến mới, không phải biến được gán lại.
+func (f *file) declarationSlot(stmt ast.Stmt) (slot, bool) {
+	var names []ast.Expr
+	var value ast.Expr
+	var current uint64
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		if stmt.Tok != token.DEFINE {
+			return slot{}, false
+		}
+		names, value = stmt.Lhs, stmt.Rhs[0]
+	case *ast.DeclStmt:
+		gd, ok := stmt.Decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR || gd.Lparen.IsValid() || len(gd.Specs) != 1 {
+			return slot{}, false
+		}
+		spec := gd.Specs[0].(*ast.ValueSpec)
+		if spec.Type != nil || len(spec.Values) == 0 {
+			return slot{}, false
+		}
+		for _, name := range spec.Names {
+			names = append(names, name)
+		}
+		value, current = spec.Values[0], 1
+	default:
+		return slot{}, false
+	}
+	// _ := v không hợp lệ vì không khai báo biến mới
+	info := f.typeInfo()
+	declared := false
+	for _, expr := range names {
+		name, ok := expr.(*ast.Ident)
+		if !ok {
+			return slot{}, false
+		}
+		if name.Name == "_" {
+			continue
+		}
+		if info.Defs[name] == nil {
+			return slot{}, false
+		}
+		declared = true
+	}
+	if !declared {
+		return slot{}, false
+	}
+	// Phần được viết lại phải nằm trên một dòng và không chứa chú thích
+	start, end := f.offset(stmt.Pos()), f.offset(value.Pos())
+	if f.line(stmt.Pos()) != f.line(value.Pos()) || strings.Contains(string(f.src[start:end]), "/") {
+		return slot{}, false
+	}
+	list := string(f.src[f.offset(names[0].Pos()):f.offset(names[len(names)-1].End())])
+	return slot{
+		pos:   stmt.Pos(),
+		kind:  slotDeclaration,
+		bits:  1,
+		value: current,
+		write: func(value uint64) []edit {
+			text := list + " := "
+			if value == 1 {
+				text = "var " + list + " = "
+			}
+			return []edit{{start, end, text}}
+		},
+	}, true
+}
+
+// commentSlot - Câu lệnh một dòng có đúng một chú thích //: ở cuối dòng (0) hoặc trên dòng
+// riêng ngay phía trên (1). Dòng phía trên phải không phải dòng chú thích để chú thích
+// không bị gộp vào một khối chú thích khác khi chuyển lên.
+func (f *file) commentSlot(stmt ast.Stmt) (slot, bool) {
+	line := f.line(stmt.Pos())
+	if f.line(stmt.End()) != line {
+		return slot{}, false
+	}
+	lineStart := f.lineStart(stmt.Pos())
+	if strings.TrimSpace(string(f.src[lineStart:f.offset(stmt.Pos())])) != "" {
+		return slot{}, false
+	}
+	trailing := f.commentsOnLine(line, stmt.End())
+	commentOnly := f.commentOnly
+
+	var c *ast.Comment
+	var current uint64
+	switch {
+	case len(trailing) == 1 && !commentOnly[line-1]:
+		c = trailing[0]
+	case len(trailing) == 0 && commentOnly[line-1] && !commentOnly[line-2]:
+		above := f.commentsOnLine(line-1, token.NoPos)
+		if len(above) != 1 || f.line(above[0].End()) != line-1 {
+			return slot{}, false
+		}
+		c, current = above[0], 1
+	default:
+		return slot{}, false
+	}
+	// Chỉ dùng chú thích thường, không dùng chỉ thị như //go:generate hay //nolint
+	if !strings.HasPrefix(c.Text, "// ") || strings.TrimSpace(c.Text) == "//" {
+		return slot{}, false
+	}
+	if current == 0 && strings.TrimSpace(string(f.src[f.offset(stmt.End()):f.offset(c.Pos())])) != "" {
+		return slot{}, false
+	}
+
+	stmtEnd := f.offset(stmt.End())
+	indent := string(f.src[lineStart:f.offset(stmt.Pos())])
+	return slot{
+		pos:   stmt.Pos(),
+		kind:  slotComment,
+		bits:  1,
+		value: current,
+		write: func(value uint64) []edit {
+			if value == 1 {
+				return []edit{
+					{lineStart, lineStart, indent + c.Text + "\n"},
+					{stmtEnd, f.offset(c.End()), ""},
+				}
+			}
+			commentStart := f.lineStart(c.Pos())
+			return []edit{
+				{commentStart, f.offset(c.End()) + 1, ""},
+				{stmtEnd, stmtEnd, " " + c.Text},
+			}
+		},
+	}, true
+}
+
+// lineStart - Vị trí byte đầu dòng chứa pos
+func (f *file) lineStart(pos token.Pos) int {
+	return f.offset(f.tf.LineStart(f.line(pos)))
+}
+
+// commentsOnLine - Các chú thích bắt đầu trên dòng line, sau vị trí after nếu có
+func (f *file) commentsOnLine(line int, after token.Pos) []*ast.Comment {
+	f.indexComments()
+	var comments []*ast.Comment
+	for _, c := range f.comments[line] {
+		if !after.IsValid() || c.Pos() >= after {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// indexComments - Lập chỉ mục các chú thích theo dòng bắt đầu và các dòng thuộc về một
+// chú thích đứng đầu dòng
+func (f *file) indexComments() {
+	if f.comments != nil {
+		return
+	}
+	f.comments = make(map[int][]*ast.Comment)
+	f.commentOnly = make(map[int]bool)
+	for _, group := range f.ast.Comments {
+		for _, c := range group.List {
+			line := f.line(c.Pos())
+			f.comments[line] = append(f.comments[line], c)
+			if strings.TrimSpace(string(f.src[f.lineStart(c.Pos()):f.offset(c.Pos())])) != "" {
+				continue
+			}
+			for l := line; l <= f.line(c.End()); l++ {
+				f.commentOnly[l] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package gosource giấu dữ liệu trong mã nguồn Go bằng các thay đổi không làm đổi chương
+// trình: thứ tự các nhóm import, thứ tự toán hạng của phép toán giao hoán, cách khai báo biến
+// (var x = ... hoặc x := ...) và vị trí chú thích. Kết quả luôn được định dạng bằng gofmt.
+package gosource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+)
+
+// Tên các kênh giấu tin
+const (
+	ChannelImports      = "imports"
+	ChannelOperands     = "operands"
+	ChannelDeclarations = "declarations"
+	ChannelComments     = "comments"
+)
+
+// Các lỗi của gói
+var (
+	ErrInvalidSource = errors.New("cover is not valid Go source")
+	ErrCoverTooSmall = errors.New("source code is too small for the payload")
+	// ErrRewriteFailed - Mã nguồn sau khi viết lại không đọc lại được đúng dữ liệu
+	ErrRewriteFailed = errors.New("rewritten source does not carry the payload")
+)
+
+// Channels - Các kênh được bật
+type Channels struct {
+	// Imports - Thứ tự các nhóm import (ngăn cách bằng dòng trống), floor(log2(n!)) bit
+	Imports bool
+	// Operands - Thứ tự hai toán hạng của phép toán giao hoán, 1 bit mỗi phép toán
+	Operands bool
+	// Declarations - x := ... (0) hoặc var x = ... (1), 1 bit mỗi khai báo
+	Declarations bool
+	// Comments - Chú thích cuối dòng (0) hoặc trên dòng riêng phía trên (1), 1 bit mỗi câu lệnh
+	Comments bool
+}
+
+// AllChannels - Bật tất cả các kênh
+var AllChannels = Channels{Imports: true, Operands: true, Declarations: true, Comments: true}
+
+// ParseChannels - Chuyển danh sách tên kênh thành Channels, rỗng là bật tất cả
+func ParseChannels(names []string) (Channels, error) {
+	if len(names) == 0 {
+		return AllChannels, nil
+	}
+	var channels Channels
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case ChannelImports:
+			channels.Imports = true
+		case ChannelOperands:
+			channels.Operands = true
+		case ChannelDeclarations:
+			channels.Declarations = true
+		case ChannelComments:
+			channels.Comments = true
+		default:
+			return channels, fmt.Errorf("unknown go source channel %q", name)
+		}
+	}
+	return channels, nil
+}
+
+// Encoder - Giấu và đọc dữ liệu trong mã nguồn Go qua các kênh đã chọn
+type Encoder struct {
+	Channels Channels
+}
+
+// New - Tạo encoder với các kênh đã chọn
+func New(channels Channels) *Encoder {
+	return &Encoder{Channels: channels}
+}
+
+// Embed - Giấu data vào mã nguồn bằng tất cả các kênh
+func Embed(src, data []byte) ([]byte, error) {
+	return New(AllChannels).Embed(src, data)
+}
+
+// Extract - Đọc dữ liệu đã giấu bằng tất cả các kênh
+func Extract(src []byte) ([]byte, error) {
+	return New(AllChannels).Extract(src)
+}
+
+// Capacity - Số bit tối đa có thể giấu trong mã nguồn
+func (e *Encoder) Capacity(src []byte) (int, error) {
+	f, err := parse(src)
+	if err != nil {
+		return 0, err
+	}
+	bits := 0
+	for _, s := range e.slots(f) {
+		bits += s.bits
+	}
+	return bits, nil
+}
+
+// Embed - Viết lại mã nguồn để mang data, các vị trí sau phần dữ liệu được giữ nguyên.
+// Mã nguồn được định dạng bằng gofmt trước và sau khi viết lại.
+func (e *Encoder) Embed(src, data []byte) ([]byte, error) {
+	f, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	slots := e.slots(f)
+	bits := bitutil.BytesToBits(data)
+	capacity := 0
+	for _, s := range slots {
+		capacity += s.bits
+	}
+	if len(bits) > capacity {
+		return nil, fmt.Errorf("%w: need %d bits, source holds %d", ErrCoverTooSmall, len(bits), capacity)
+	}
+
+	var edits []edit
+	rest := bits
+	for _, s := range slots {
+		if len(rest) == 0 {
+			break
+		}
+		// Vị trí cuối có thể mang nhiều bit hơn phần còn lại, phần thiếu được đệm bằng 0
+		var value uint64
+		for i := 0; i < s.bits; i++ {
+			value <<= 1
+			if i < len(rest) {
+				value |= uint64(rest[i])
+			}
+		}
+		rest = rest[min(s.bits, len(rest)):]
+		if value != s.value {
+			edits = append(edits, s.write(value)...)
+		}
+	}
+
+	out, err := format.Source(applyEdits(f.src, edits))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRewriteFailed, err)
+	}
+	// Kiểm tra lại bằng cách đọc dữ liệu từ kết quả
+	check, err := parse(out)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRewriteFailed, err)
+	}
+	if got := e.bits(check); len(got) < len(bits) || !bytes.Equal(got[:len(bits)], bits) {
+		return nil, ErrRewriteFailed
+	}
+	return out, nil
+}
+
+// Extract - Đọc toàn bộ các byte mang trong mã nguồn
+func (e *Encoder) Extract(src []byte) ([]byte, error) {
+	f, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return bitutil.BitsToBytes(e.bits(f)), nil
+}
+
+// bits - Các bit đang được mang theo thứ tự các vị trí
+func (e *Encoder) bits(f *file) []byte {
+	var bits []byte
+	for _, s := range e.slots(f) {
+		bits = bitutil.AppendUintBits(bits, s.value, s.bits)
+	}
+	return bits
+}
+
+// file - Mã nguồn đã định dạng cùng cây cú pháp
+type file struct {
+	src  []byte
+	fset *token.FileSet
+	ast  *ast.File
+	tf   *token.File
+
+	// info, comments, commentOnly - Thông tin kiểu và chỉ mục chú thích theo dòng, lập khi cần
+	info        *types.Info
+	comments    map[int][]*ast.Comment
+	commentOnly map[int]bool
+}
+
+// parse - Định dạng mã nguồn bằng gofmt rồi phân tích, nhờ vậy dữ liệu vẫn đọc được khi
+// mã nguồn bị định dạng lại
+func parse(src []byte) (*file, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSource, err)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "source.go", formatted, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSource, err)
+	}
+	return &file{src: formatted, fset: fset, ast: f, tf: fset.File(f.Pos())}, nil
+}
+
+// offset - Vị trí byte của pos trong mã nguồn
+func (f *file) offset(pos token.Pos) int {
+	return f.tf.Offset(pos)
+}
+
+// line - Số dòng của pos
+func (f *file) line(pos token.Pos) int {
+	return f.tf.Line(pos)
+}
+
+// text - Mã nguồn của node
+func (f *file) text(n ast.Node) string {
+	return string(f.src[f.offset(n.Pos()):f.offset(n.End())])
+}
+
+// edit - Thay đoạn [start, end) của mã nguồn bằng text
+type edit struct {
+	start, end int
+	text       string
+}
+
+// applyEdits - Áp dụng các thay đổi không chồng lên nhau
+func applyEdits(src []byte, edits []edit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	out := make([]byte, 0, len(src)+len(edits)*8)
+	prev := 0
+	for _, e := range edits {
+		out = append(out, src[prev:e.start]...)
+		out = append(out, e.text...)
+		prev = e.end
+	}
+	return append(out, src[prev:]...)
+}
@@ -0,0 +1,267 @@
+package stego
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Đơn vị văn bản mang chữ cái của thông điệp
+const (
+	AcrosticUnitLine     = "line"
+	AcrosticUnitSentence = "sentence"
+)
+
+// Giới hạn của bộ soạn văn bản acrostic
+const (
+	// DefaultAcrosticMaxPosition - Vị trí từ lớn nhất khi vị trí được sinh từ passphrase
+	DefaultAcrosticMaxPosition = 3
+	maxAcrosticPosition        = 20
+	// Số từ đệm sau từ mang tin khi ghép câu từ danh sách từ
+	minAcrosticFiller = 2
+	maxAcrosticFiller = 5
+)
+
+// Các lỗi của bộ soạn văn bản acrostic
+var (
+	ErrEmptyAcrosticSecret = errors.New("secret has no letters or digits")
+	ErrNoAcrosticSource    = errors.New("a corpus or a word list is required")
+	ErrNoAcrosticCandidate = errors.New("no sentence or word can carry the letter")
+)
+
+// AcrosticOptions - Cách đặt chữ cái của thông điệp trong văn bản.
+// Chữ cái là chữ đầu của từ thứ Word trong mỗi dòng/câu; khi có Passphrase, vị trí từ
+// của từng dòng/câu được sinh từ passphrase trong khoảng 1..MaxPosition.
+type AcrosticOptions struct {
+	Unit        string
+	Word        int
+	Passphrase  string
+	MaxPosition int
+}
+
+// AcrosticComposer - Soạn văn bản phủ từ các câu của một corpus, hoặc ghép câu từ
+// danh sách từ khi corpus không có câu phù hợp
+type AcrosticComposer struct {
+	sentences []string
+	words     []string
+}
+
+// NewAcrosticComposer - Tạo bộ soạn từ corpus (văn bản tự do) và/hoặc danh sách từ
+func NewAcrosticComposer(corpus string, words []string) (*AcrosticComposer, error) {
+	c := &AcrosticComposer{}
+	for _, sentence := range splitSentences(corpus) {
+		text := strings.Join(strings.Fields(corpus[sentence.start:sentence.end]), " ")
+		// Câu không có dấu kết thúc được thêm dấu chấm để vẫn tách được khi ghép thành đoạn
+		last, _ := utf8.DecodeLastRuneInString(text)
+		if !strings.ContainsRune(".!?…", last) {
+			if !unicode.IsLetter(last) && !unicode.IsDigit(last) {
+				continue
+			}
+			text += "."
+		}
+		if len(synonymTokens(text)) > 0 {
+			c.sentences = append(c.sentences, text)
+		}
+	}
+	for _, word := range words {
+		// Chỉ nhận từ đơn gồm toàn chữ và số
+		word = strings.TrimSpace(word)
+		if tokens := synonymTokens(word); len(tokens) == 1 && tokens[0] == (textRange{start: 0, end: len(word)}) {
+			c.words = append(c.words, word)
+		}
+	}
+	if len(c.sentences) == 0 && len(c.words) == 0 {
+		return nil, ErrNoAcrosticSource
+	}
+	return c, nil
+}
+
+// Compose - Soạn văn bản mà các chữ cái ở vị trí đã chọn ghép thành secret
+func (c *AcrosticComposer) Compose(secret string, opts AcrosticOptions) (string, error) {
+	letters := foldAcrosticSecret(secret)
+	if len(letters) == 0 {
+		return "", ErrEmptyAcrosticSecret
+	}
+	positions, err := acrosticPositions(opts, len(letters))
+	if err != nil {
+		return "", err
+	}
+
+	units := make([]string, len(letters))
+	used := make(map[int]bool)
+	for i, letter := range letters {
+		unit, ok := c.pickSentence(letter, positions[i], used)
+		if !ok {
+			unit, ok = c.buildSentence(letter, positions[i])
+		}
+		if !ok {
+			return "", fmt.Errorf("%w: %q at word %d", ErrNoAcrosticCandidate, letter, positions[i])
+		}
+		units[i] = unit
+	}
+
+	if opts.Unit == AcrosticUnitSentence {
+		return strings.Join(units, " "), nil
+	}
+	return strings.Join(units, "\n"), nil
+}
+
+// pickSentence - Chọn ngẫu nhiên một câu trong corpus có từ thứ position bắt đầu bằng letter,
+// ưu tiên câu chưa dùng
+func (c *AcrosticComposer) pickSentence(letter rune, position int, used map[int]bool) (string, bool) {
+	var fresh, all []int
+	for i, sentence := range c.sentences {
+		if acrosticLetter(sentence, position) != letter {
+			continue
+		}
+		all = append(all, i)
+		if !used[i] {
+			fresh = append(fresh, i)
+		}
+	}
+	if len(fresh) > 0 {
+		all = fresh
+	}
+	if len(all) == 0 {
+		return "", false
+	}
+	i := all[rand.IntN(len(all))]
+	used[i] = true
+	return c.sentences[i], true
+}
+
+// buildSentence - Ghép một câu từ danh sách từ với từ mang tin ở vị trí position
+func (c *AcrosticComposer) buildSentence(letter rune, position int) (string, bool) {
+	var carriers []string
+	for _, word := range c.words {
+		if acrosticLetter(word, 1) == letter {
+			carriers = append(carriers, word)
+		}
+	}
+	if len(carriers) == 0 {
+		return "", false
+	}
+
+	words := make([]string, 0, position+maxAcrosticFiller)
+	for i := 1; i < position; i++ {
+		words = append(words, c.words[rand.IntN(len(c.words))])
+	}
+	words = append(words, carriers[rand.IntN(len(carriers))])
+	for i := minAcrosticFiller + rand.IntN(maxAcrosticFiller-minAcrosticFiller+1); i > 0; i-- {
+		words = append(words, c.words[rand.IntN(len(c.words))])
+	}
+	sentence := strings.Join(words, " ")
+	r, n := utf8.DecodeRuneInString(sentence)
+	return string(unicode.ToUpper(r)) + sentence[n:] + ".", true
+}
+
+// ExtractAcrostic - Đọc các chữ cái ở vị trí đã chọn của từng dòng/câu
+func ExtractAcrostic(text string, opts AcrosticOptions) (string, error) {
+	var units []string
+	if opts.Unit == AcrosticUnitSentence {
+		for _, sentence := range splitSentences(text) {
+			units = append(units, text[sentence.start:sentence.end])
+		}
+	} else {
+		for _, line := range strings.Split(text, "\n") {
+			if strings.TrimSpace(line) != "" {
+				units = append(units, line)
+			}
+		}
+	}
+	if len(units) == 0 {
+		return "", ErrNoHiddenData
+	}
+
+	positions, err := acrosticPositions(opts, len(units))
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i, unit := range units {
+		if letter := acrosticLetter(unit, positions[i]); letter != 0 {
+			sb.WriteRune(letter)
+		}
+	}
+	if sb.Len() == 0 {
+		return "", ErrNoHiddenData
+	}
+	return sb.String(), nil
+}
+
+// acrosticPositions - Vị trí từ (bắt đầu từ 1) của n dòng/câu đầu tiên
+func acrosticPositions(opts AcrosticOptions, n int) ([]int, error) {
+	positions := make([]int, n)
+	if opts.Passphrase == "" {
+		word := opts.Word
+		if word == 0 {
+			word = 1
+		}
+		if word < 1 || word > maxAcrosticPosition {
+			return nil, fmt.Errorf("word position must be between 1 and %d", maxAcrosticPosition)
+		}
+		for i := range positions {
+			positions[i] = word
+		}
+		return positions, nil
+	}
+
+	maxPosition := opts.MaxPosition
+	if maxPosition == 0 {
+		maxPosition = DefaultAcrosticMaxPosition
+	}
+	if maxPosition < 1 || maxPosition > maxAcrosticPosition {
+		return nil, fmt.Errorf("max position must be between 1 and %d", maxAcrosticPosition)
+	}
+	// Dãy vị trí là HMAC-SHA256(khóa, chỉ số) của khóa dẫn xuất từ passphrase
+	key := derivePositionKey(opts.Passphrase, "acrostic-positions")
+	mac := hmac.New(sha256.New, key)
+	var counter [8]byte
+	for i := range positions {
+		mac.Reset()
+		binary.BigEndian.PutUint64(counter[:], uint64(i))
+		mac.Write(counter[:])
+		sum := mac.Sum(nil)
+		positions[i] = 1 + int(binary.BigEndian.Uint64(sum)%uint64(maxPosition))
+	}
+	return positions, nil
+}
+
+// acrosticLetter - Chữ cái đầu (đã bỏ dấu, chữ thường) của từ thứ position, 0 nếu không có
+func acrosticLetter(text string, position int) rune {
+	tokens := synonymTokens(text)
+	if position < 1 || position > len(tokens) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(text[tokens[position-1].start:])
+	return foldAcrosticLetter(r)
+}
+
+// foldAcrosticSecret - Các chữ cái và chữ số của secret sau khi bỏ dấu
+func foldAcrosticSecret(secret string) []rune {
+	var letters []rune
+	for _, r := range norm.NFC.String(secret) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			letters = append(letters, foldAcrosticLetter(r))
+		}
+	}
+	return letters
+}
+
+// foldAcrosticLetter - Bỏ dấu và chuyển chữ thường để "Ấ", "â" và "a" là cùng một chữ
+func foldAcrosticLetter(r rune) rune {
+	r = unicode.ToLower(r)
+	if r == '\u0111' {
+		return 'd'
+	}
+	base, _ := utf8.DecodeRuneInString(norm.NFD.String(string(r)))
+	return base
+}
@@ -0,0 +1,82 @@
+package stego
+
+import (
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AcrosticFields - Vị trí chữ cái trong văn bản, phải giống nhau khi soạn và khi đọc
+type AcrosticFields struct {
+	// Unit - "line" (mặc định) hoặc "sentence"
+	Unit string `json:"unit" binding:"omitempty,oneof=line sentence"`
+	// Word - Vị trí từ mang chữ cái trong mỗi dòng/câu, mặc định 1
+	Word int `json:"word" binding:"omitempty,gte=1,lte=20"`
+	// Passphrase - Sinh vị trí từ riêng cho từng dòng/câu thay cho Word
+	Passphrase  string `json:"passphrase"`
+	MaxPosition int    `json:"max_position" binding:"omitempty,gte=1,lte=20"`
+}
+
+func (f *AcrosticFields) options() AcrosticOptions {
+	return AcrosticOptions{
+		Unit:        f.Unit,
+		Word:        f.Word,
+		Passphrase:  f.Passphrase,
+		MaxPosition: f.MaxPosition,
+	}
+}
+
+// AcrosticComposeRequest - Request body cho soạn văn bản acrostic
+type AcrosticComposeRequest struct {
+	AcrosticFields
+	Secret string `json:"secret" binding:"required,max=256"`
+	// Corpus - Văn bản nguồn, các câu được chọn nguyên vẹn
+	Corpus string `json:"corpus" binding:"required_without=Words"`
+	// Words - Danh sách từ để ghép câu khi corpus không có câu phù hợp
+	Words []string `json:"words" binding:"required_without=Corpus"`
+}
+
+// AcrosticExtractRequest - Request body cho đọc chữ cái từ văn bản acrostic
+type AcrosticExtractRequest struct {
+	AcrosticFields
+	Text string `json:"text" binding:"required"`
+}
+
+// AcrosticExtractResponse - Các chữ cái đọc được (đã bỏ dấu, chữ thường)
+type AcrosticExtractResponse struct {
+	Secret string `json:"secret"`
+}
+
+// AcrosticCompose - Soạn văn bản mà chữ đầu các dòng/câu ghép thành secret
+func (h *Handler) AcrosticCompose(c *gin.Context) {
+	var req AcrosticComposeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	composer, err := NewAcrosticComposer(req.Corpus, req.Words)
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := composer.Compose(req.Secret, req.options())
+	respondEmbedded(c, text, err)
+}
+
+// AcrosticExtract - Đọc secret từ chữ đầu các dòng/câu
+func (h *Handler) AcrosticExtract(c *gin.Context) {
+	var req AcrosticExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	secret, err := ExtractAcrostic(req.Text, req.options())
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	utils.RespondWithSuccess(c, http.StatusOK, "Message extracted successfully", AcrosticExtractResponse{Secret: secret})
+}
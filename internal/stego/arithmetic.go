@@ -0,0 +1,88 @@
+package stego
+
+// Tham số của bộ mã hóa số học 32 bit
+const (
+	codeTop     = 1<<32 - 1
+	codeHalf    = 1 << 31
+	codeQuarter = 1 << 30
+	// maxFrequencyTotal - Tổng tần suất tối đa của một phân phối, đủ nhỏ để mọi ký hiệu
+	// luôn có khoảng rộng ít nhất 1 khi khoảng hiện tại lớn hơn codeQuarter
+	maxFrequencyTotal = 1 << 16
+)
+
+// arithmeticCoder - Bộ mã hóa số học dùng chung cho hai chiều.
+// Khi giấu tin, dữ liệu được coi là một chuỗi bit đã mã hóa và được "giải mã" thành các
+// lựa chọn token (next khác nil). Khi trích xuất, các token được mã hóa lại và các bit đã
+// chắc chắn (out) chính là phần đầu của chuỗi bit ban đầu.
+type arithmeticCoder struct {
+	low, high uint64
+	// pending - Số bit đang chờ do khoảng nằm quanh điểm giữa (trường hợp E3)
+	pending int
+	out     []byte
+
+	value uint64
+	next  func() byte
+}
+
+// newArithmeticEncoder - Bộ mã hóa: chỉ thu các bit đã chắc chắn
+func newArithmeticEncoder() *arithmeticCoder {
+	return &arithmeticCoder{high: codeTop}
+}
+
+// newArithmeticDecoder - Bộ giải mã đọc bit từ next, 32 bit đầu là giá trị ban đầu
+func newArithmeticDecoder(next func() byte) *arithmeticCoder {
+	a := &arithmeticCoder{high: codeTop, next: next}
+	for i := 0; i < 32; i++ {
+		a.value = a.value<<1 | uint64(next())
+	}
+	return a
+}
+
+// decode - Chọn ký hiệu có khoảng chứa giá trị hiện tại. cum là tần suất cộng dồn,
+// ký hiệu i chiếm [cum[i], cum[i+1]).
+func (a *arithmeticCoder) decode(cum []uint64) int {
+	total := cum[len(cum)-1]
+	width := a.high - a.low + 1
+	target := ((a.value-a.low+1)*total - 1) / width
+	symbol := 0
+	for symbol < len(cum)-2 && cum[symbol+1] <= target {
+		symbol++
+	}
+	a.narrow(cum[symbol], cum[symbol+1], total)
+	return symbol
+}
+
+// narrow - Thu hẹp khoảng về [lo, hi) trên total và đẩy ra các bit đã chắc chắn
+func (a *arithmeticCoder) narrow(lo, hi, total uint64) {
+	width := a.high - a.low + 1
+	a.high = a.low + width*hi/total - 1
+	a.low = a.low + width*lo/total
+	for {
+		var offset uint64
+		switch {
+		case a.high < codeHalf:
+			a.emit(0)
+		case a.low >= codeHalf:
+			a.emit(1)
+			offset = codeHalf
+		case a.low >= codeQuarter && a.high < codeHalf+codeQuarter:
+			a.pending++
+			offset = codeQuarter
+		default:
+			return
+		}
+		a.low = (a.low - offset) << 1
+		a.high = (a.high-offset)<<1 | 1
+		if a.next != nil {
+			a.value = (a.value-offset)<<1 | uint64(a.next())
+		}
+	}
+}
+
+// emit - Ghi một bit và các bit đang chờ (ngược giá trị với bit vừa ghi)
+func (a *arithmeticCoder) emit(bit byte) {
+	a.out = append(a.out, bit)
+	for ; a.pending > 0; a.pending-- {
+		a.out = append(a.out, bit^1)
+	}
+}
@@ -0,0 +1,254 @@
+package stego
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CapacityOptions - Các lớp xử lý được tính vào dung lượng
+type CapacityOptions struct {
+	Method  string
+	Encrypt bool
+	ECC     *ECCOptions
+	// PayloadSize - Kích thước payload dự định giấu (byte), 0 nếu chỉ cần dung lượng tối đa.
+	// Không biết nội dung nên payload được tính như không nén được; kết quả là cận trên vì
+	// nén chỉ được dùng khi làm payload nhỏ đi.
+	PayloadSize int
+	// Message - Message dự định giấu. Nếu có, kích thước payload lấy từ message và message
+	// được nén như khi giấu tin để báo đúng kích thước container.
+	Message string
+}
+
+// Overhead - Số byte mỗi lớp xử lý thêm vào payload
+type Overhead struct {
+	Header int `json:"header"`
+	// Compression - Số byte nén bớt được (số âm), 0 nếu payload không được nén
+	Compression int `json:"compression"`
+	Encryption  int `json:"encryption"`
+	ECC         int `json:"ecc"`
+	Total       int `json:"total"`
+}
+
+// CoverRequirement - Lượng văn bản phủ cần cho một payload
+type CoverRequirement struct {
+	Bits       int `json:"bits"`
+	Characters int `json:"characters"`
+	Words      int `json:"words"`
+	// Estimated - true nếu văn bản phủ hiện tại không đủ và số liệu được ngoại suy
+	Estimated bool `json:"estimated"`
+}
+
+// CapacityReport - Kết quả ước lượng dung lượng
+type CapacityReport struct {
+	Method          string            `json:"method"`
+	CapacityBits    int               `json:"capacity_bits"`
+	MaxPayloadBytes int               `json:"max_payload_bytes"`
+	Overhead        Overhead          `json:"overhead"`
+	PayloadSize     int               `json:"payload_size"`
+	Fits            bool              `json:"fits"`
+	Required        *CoverRequirement `json:"required,omitempty"`
+	// Sentences - Dung lượng từng câu, chỉ có với các phương pháp hỗ trợ
+	Sentences []SentenceCapacity `json:"sentences,omitempty"`
+}
+
+// SentenceCapacity - Dung lượng của một câu trong văn bản phủ
+type SentenceCapacity struct {
+	Sentence string `json:"sentence"`
+	Bits     int    `json:"bits"`
+}
+
+// SentenceCapacitor - Phương pháp có thể báo dung lượng theo từng câu
+type SentenceCapacitor interface {
+	SentenceCapacity(cover string) []SentenceCapacity
+}
+
+// Capacity - Ước lượng dung lượng của văn bản phủ với các lớp xử lý đã chọn
+func (s *StegoService) Capacity(cover string, opts CapacityOptions) (*CapacityReport, error) {
+	m, err := s.getMethod(opts.Method)
+	if err != nil {
+		return nil, err
+	}
+	capacityBits := m.Capacity(cover)
+
+	payloadSize, compression := opts.PayloadSize, 0
+	if opts.Message != "" {
+		payloadSize = len(opts.Message)
+		if compressed, ok := compressPayload([]byte(opts.Message)); ok {
+			compression = len(compressed) - payloadSize
+		}
+	}
+	report := &CapacityReport{
+		Method:       m.Name(),
+		CapacityBits: capacityBits,
+		PayloadSize:  payloadSize,
+	}
+	report.MaxPayloadBytes, err = maxPayload(capacityBits/8, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	size, overhead, err := containerSize(payloadSize, compression, opts)
+	if err != nil {
+		return nil, err
+	}
+	report.Overhead = overhead
+	report.Fits = size*8 <= capacityBits
+	if payloadSize > 0 {
+		report.Required = coverRequirement(m, cover, size*8)
+	}
+	if sc, ok := m.(SentenceCapacitor); ok {
+		report.Sentences = sc.SentenceCapacity(cover)
+	}
+	return report, nil
+}
+
+// containerSize - Kích thước container cho một payload và chi phí của từng lớp.
+// compression là số byte nén bớt được (số âm hoặc 0), nén được làm trước khi mã hóa.
+func containerSize(payloadSize, compression int, opts CapacityOptions) (int, Overhead, error) {
+	overhead := Overhead{Header: ContainerHeaderSize, Compression: compression}
+	body := payloadSize + compression
+	if opts.Encrypt {
+		overhead.Header += kdfParamsSize
+		overhead.Encryption = encryptionOverhead
+		body += encryptionOverhead
+	}
+	if opts.ECC != nil {
+		params, err := newECCParams(*opts.ECC, body)
+		if err != nil {
+			return 0, overhead, err
+		}
+		overhead.Header += eccParamsSize
+		flags := FlagECC
+		if opts.Encrypt {
+			flags |= FlagEncrypted
+		}
+		// Parity của payload cộng parity bảo vệ header
+		overhead.ECC = params.EncodedSize() - body + headerECCSize(flags)
+		body = params.EncodedSize()
+	}
+	overhead.Total = overhead.Header + overhead.Compression + overhead.Encryption + overhead.ECC
+	return payloadSize + overhead.Total, overhead, nil
+}
+
+// maxPayload - Kích thước payload lớn nhất vừa với capacityBytes
+func maxPayload(capacityBytes int, opts CapacityOptions) (int, error) {
+	// Kiểm tra tùy chọn một lần, các lần tính sau không thể lỗi
+	if _, _, err := containerSize(0, 0, opts); err != nil {
+		return 0, err
+	}
+	fits := func(n int) bool {
+		size, _, _ := containerSize(n, 0, opts)
+		return size <= capacityBytes
+	}
+
+	// Kích thước container tăng gần như đơn điệu theo payload; tìm nhị phân rồi
+	// lùi lại vài byte để bù cho việc làm tròn số byte parity
+	lo, hi := 0, capacityBytes
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	for lo > 0 && !fits(lo) {
+		lo--
+	}
+	return lo, nil
+}
+
+// coverRequirement - Tìm đoạn đầu ngắn nhất của văn bản phủ đủ chứa bits.
+// Nếu cả văn bản không đủ, hoặc phương pháp dùng tài liệu có cấu trúc mà đoạn đầu không phải
+// tài liệu hợp lệ, thì ước lượng theo dung lượng trung bình mỗi từ của cả văn bản.
+func coverRequirement(m Method, cover string, bits int) *CoverRequirement {
+	ends := wordEndOffsets(cover)
+	req := &CoverRequirement{Bits: bits}
+	if len(ends) == 0 {
+		req.Estimated = true
+		return req
+	}
+
+	total := m.Capacity(cover)
+	if total < bits || m.Capabilities().Structured {
+		req.Estimated = true
+		if total == 0 {
+			return req
+		}
+		ratio := float64(bits) / float64(total)
+		req.Words = int(math.Ceil(float64(len(ends)) * ratio))
+		req.Characters = int(math.Ceil(float64(utf8.RuneCountInString(cover)) * ratio))
+		return req
+	}
+
+	lo, hi := 1, len(ends)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if m.Capacity(cover[:ends[mid-1]]) >= bits {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	req.Words = lo
+	req.Characters = utf8.RuneCountInString(cover[:ends[lo-1]])
+	return req
+}
+
+// wordEndOffsets - Vị trí byte ngay sau mỗi từ trong văn bản
+func wordEndOffsets(text string) []int {
+	var ends []int
+	inWord := false
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if inWord {
+				ends = append(ends, i)
+			}
+			inWord = false
+			continue
+		}
+		inWord = true
+	}
+	if inWord {
+		ends = append(ends, len(text))
+	}
+	return ends
+}
+
+// textRange - Một đoạn văn bản theo vị trí byte
+type textRange struct {
+	start, end int
+}
+
+// splitSentences - Tách văn bản thành các câu: câu kết thúc sau ".", "!", "?", "…"
+// có khoảng trắng theo sau, hoặc tại cuối dòng. Khoảng trắng hai đầu câu bị bỏ.
+func splitSentences(text string) []textRange {
+	var sentences []textRange
+	start := 0
+	add := func(end int) {
+		s := strings.TrimSpace(text[start:end])
+		if s != "" {
+			offset := start + strings.Index(text[start:end], s)
+			sentences = append(sentences, textRange{start: offset, end: offset + len(s)})
+		}
+		start = end
+	}
+	for i, r := range text {
+		switch {
+		case r == '\n':
+			add(i)
+		case strings.ContainsRune(".!?…", r):
+			next := i + utf8.RuneLen(r)
+			if next < len(text) {
+				if following, _ := utf8.DecodeRuneInString(text[next:]); !unicode.IsSpace(following) {
+					continue
+				}
+			}
+			add(next)
+		}
+	}
+	add(len(text))
+	return sentences
+}
@@ -0,0 +1,76 @@
+package stego
+
+import (
+	"bytes"
+	"compress/flate"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// maxDecompressedSize - Kích thước tối đa sau khi giải nén, tránh dữ liệu độc hại nở ra quá lớn
+const maxDecompressedSize = 16 << 20
+
+// Payload được nén bằng DEFLATE (không có header zlib) với từ điển dựng sẵn.
+// Từ điển là một phần của định dạng: bên trích xuất phải dùng đúng từ điển này.
+//
+//go:embed compression/dictionary.txt
+var compressionDictionaryFile string
+
+var (
+	compressionDictionaryOnce sync.Once
+	compressionDictionary     []byte
+)
+
+// presetDictionary - Từ điển nén đã bỏ các dòng chú thích
+func presetDictionary() []byte {
+	compressionDictionaryOnce.Do(func() {
+		var lines []string
+		for _, line := range strings.Split(compressionDictionaryFile, "\n") {
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			lines = append(lines, line)
+		}
+		compressionDictionary = []byte(strings.Join(lines, "\n"))
+	})
+	return compressionDictionary
+}
+
+// compressPayload - Nén payload, ok = false nếu bản nén không nhỏ hơn bản gốc
+func compressPayload(payload []byte) ([]byte, bool) {
+	if len(payload) == 0 {
+		return nil, false
+	}
+	var buf bytes.Buffer
+	w, err := flate.NewWriterDict(&buf, flate.BestCompression, presetDictionary())
+	if err != nil {
+		return nil, false
+	}
+	if _, err := w.Write(payload); err != nil {
+		return nil, false
+	}
+	if err := w.Close(); err != nil {
+		return nil, false
+	}
+	if buf.Len() >= len(payload) {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// decompressPayload - Giải nén payload đã nén bằng compressPayload
+func decompressPayload(data []byte) ([]byte, error) {
+	r := flate.NewReaderDict(bytes.NewReader(data), presetDictionary())
+	defer r.Close()
+	payload, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	if len(payload) > maxDecompressedSize {
+		return nil, fmt.Errorf("%w: decompressed payload exceeds %d bytes", ErrCorrupted, maxDecompressedSize)
+	}
+	return payload, nil
+}
@@ -0,0 +1,315 @@
+package stego
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// Định dạng container được ghi trước dữ liệu trong mọi phương pháp (big-endian):
+//
+//	magic    [2]byte  "DH"
+//	version  uint8    phiên bản định dạng
+//	method   uint8    ID phương pháp giấu tin
+//	flags    uint8    các lớp xử lý đã áp dụng cho payload
+//	length   uint32   độ dài payload đã ghi (sau khi thêm parity nếu có ECC)
+//	crc32    uint32   CRC-32 (IEEE) của phần mở rộng và payload trước khi thêm parity
+//	ext      []byte   phần mở rộng của header, theo thứ tự bit của flags
+//	payload  [length]byte
+//
+// Phần mở rộng hiện có: tham số KDF khi bật FlagEncrypted, tham số ECC khi bật FlagECC,
+// tham số mảnh bí mật khi bật FlagShare.
+// Khi bật FlagFile, payload gốc (trước khi nén và mã hóa) bắt đầu bằng metadata của file
+// để tên file cũng được mã hóa, xem marshalHiddenFile.
+//
+// Từ phiên bản 2, khi bật FlagECC header cũng được bảo vệ: 13 byte cố định là một codeword
+// Reed-Solomon riêng, phần mở rộng là một codeword thứ hai, mỗi codeword có parity theo
+// headerParity. Mã là hệ thống nên header vẫn đứng đầu và đọc được như phiên bản 1 khi
+// không có lỗi.
+const (
+	// ContainerVersion - Phiên bản định dạng container hiện tại
+	ContainerVersion uint8 = 2
+	// ContainerHeaderSize - Kích thước header cố định (byte)
+	ContainerHeaderSize = 13
+
+	// protectedHeaderVersion - Phiên bản đầu tiên bảo vệ header bằng ECC
+	protectedHeaderVersion uint8 = 2
+	// minHeaderParity - Số byte parity tối thiểu của mỗi codeword header
+	minHeaderParity = 8
+)
+
+var containerMagic = [2]byte{'D', 'H'}
+
+// Flags - Các lớp xử lý đã áp dụng cho payload
+type Flags uint8
+
+const (
+	// FlagCompressed - Payload đã được nén
+	FlagCompressed Flags = 1 << iota
+	// FlagEncrypted - Payload đã được mã hóa
+	FlagEncrypted
+	// FlagECC - Payload có mã sửa lỗi
+	FlagECC
+	// FlagFile - Payload là file kèm metadata thay vì message
+	FlagFile
+	// FlagShare - Payload là một mảnh Shamir của bí mật được chia cho nhiều văn bản.
+	// Khi đó FlagCompressed và FlagFile mô tả bí mật sau khi ghép, không phải mảnh.
+	FlagShare
+)
+
+// knownFlags - Các cờ mà phiên bản hiện tại hiểu được
+const knownFlags = FlagCompressed | FlagEncrypted | FlagECC | FlagFile | FlagShare
+
+// Has - Kiểm tra cờ có được bật không
+func (f Flags) Has(flag Flags) bool {
+	return f&flag != 0
+}
+
+// Các lỗi khi đọc container
+var (
+	ErrTruncated          = errors.New("hidden data is truncated")
+	ErrCorrupted          = errors.New("hidden data is corrupted (checksum mismatch)")
+	ErrUnsupportedVersion = errors.New("unsupported hidden data format version")
+)
+
+// Container - Dữ liệu giấu kèm header tự mô tả
+type Container struct {
+	Version  uint8
+	MethodID uint8
+	Flags    Flags
+	KDF      *KDFParams
+	ECC      *ECCParams
+	Share    *ShareParams
+	Payload  []byte
+
+	// Corrected - Số symbol được sửa bởi ECC khi đọc container
+	Corrected int
+}
+
+// extensions - Ghi phần mở rộng của header theo thứ tự bit của flags
+func (c *Container) extensions() []byte {
+	var ext []byte
+	if c.Flags.Has(FlagEncrypted) && c.KDF != nil {
+		ext = append(ext, c.KDF.marshal()...)
+	}
+	if c.Flags.Has(FlagECC) && c.ECC != nil {
+		ext = append(ext, c.ECC.marshal()...)
+	}
+	if c.Flags.Has(FlagShare) && c.Share != nil {
+		ext = append(ext, c.Share.marshal()...)
+	}
+	return ext
+}
+
+// extensionsSize - Độ dài phần mở rộng của header theo flags
+func extensionsSize(flags Flags) int {
+	size := 0
+	if flags.Has(FlagEncrypted) {
+		size += kdfParamsSize
+	}
+	if flags.Has(FlagECC) {
+		size += eccParamsSize
+	}
+	if flags.Has(FlagShare) {
+		size += shareParamsSize
+	}
+	return size
+}
+
+// headerParity - Số byte parity bảo vệ một phần header dài size byte
+func headerParity(size int) int {
+	return max(minHeaderParity, (size+1)/2)
+}
+
+// headerECCSize - Số byte parity thêm vào header khi bật FlagECC
+func headerECCSize(flags Flags) int {
+	size := headerParity(ContainerHeaderSize)
+	if ext := extensionsSize(flags); ext > 0 {
+		size += headerParity(ext)
+	}
+	return size
+}
+
+// associatedData - Phần header được xác thực cùng payload khi mã hóa.
+// Không gồm length, crc32 và tham số ECC vì các trường này phụ thuộc vào ciphertext.
+func (c *Container) associatedData() []byte {
+	version := c.Version
+	if version == 0 {
+		version = ContainerVersion
+	}
+	aad := []byte{containerMagic[0], containerMagic[1], version, c.MethodID, byte(c.Flags)}
+	if c.Flags.Has(FlagEncrypted) && c.KDF != nil {
+		aad = append(aad, c.KDF.marshal()...)
+	}
+	if c.Flags.Has(FlagShare) && c.Share != nil {
+		aad = append(aad, c.Share.marshal()...)
+	}
+	return aad
+}
+
+// MarshalBinary - Ghi container thành chuỗi byte
+func (c *Container) MarshalBinary() ([]byte, error) {
+	if uint64(len(c.Payload)) > uint64(^uint32(0)) {
+		return nil, errors.New("payload is too large")
+	}
+	if c.Flags.Has(FlagEncrypted) && c.KDF == nil {
+		return nil, errors.New("encrypted container requires kdf parameters")
+	}
+	if c.Flags.Has(FlagECC) && (c.ECC == nil || int(c.ECC.Length) != len(c.Payload)) {
+		return nil, errors.New("ecc parameters do not match payload")
+	}
+	if c.Flags.Has(FlagShare) && c.Share == nil {
+		return nil, errors.New("share container requires share parameters")
+	}
+	version := c.Version
+	if version == 0 {
+		version = ContainerVersion
+	}
+	ext := c.extensions()
+
+	body := c.Payload
+	if c.Flags.Has(FlagECC) {
+		body = c.ECC.encode(c.Payload)
+	}
+
+	header := make([]byte, ContainerHeaderSize)
+	copy(header[0:2], containerMagic[:])
+	header[2] = version
+	header[3] = c.MethodID
+	header[4] = byte(c.Flags)
+	binary.BigEndian.PutUint32(header[5:9], uint32(len(body)))
+	binary.BigEndian.PutUint32(header[9:13], checksum(ext, c.Payload))
+	if c.Flags.Has(FlagECC) && version >= protectedHeaderVersion {
+		header = rsEncode(header, headerParity(len(header)))
+		if len(ext) > 0 {
+			ext = rsEncode(ext, headerParity(len(ext)))
+		}
+	}
+
+	buf := make([]byte, 0, len(header)+len(ext)+len(body))
+	buf = append(buf, header...)
+	buf = append(buf, ext...)
+	return append(buf, body...), nil
+}
+
+// decodeProtectedHeader - Sửa lỗi header được bảo vệ bằng ECC. Trả về header cố định,
+// phần còn lại với phần mở rộng đã sửa ở đầu và số symbol đã sửa; ok là false nếu dữ liệu
+// không bắt đầu bằng header được bảo vệ.
+func decodeProtectedHeader(data []byte) (header, rest []byte, corrected int, ok bool, err error) {
+	n := ContainerHeaderSize + headerParity(ContainerHeaderSize)
+	if len(data) < n {
+		return nil, nil, 0, false, nil
+	}
+	header, corrected, err = rsDecode(data[:n], headerParity(ContainerHeaderSize))
+	if err != nil || header[0] != containerMagic[0] || header[1] != containerMagic[1] ||
+		header[2] < protectedHeaderVersion || !Flags(header[4]).Has(FlagECC) {
+		return nil, nil, 0, false, nil
+	}
+
+	rest = data[n:]
+	size := extensionsSize(Flags(header[4]))
+	m := size + headerParity(size)
+	if len(rest) < m {
+		return nil, nil, 0, true, fmt.Errorf("%w: protected header extensions need %d bytes, got %d", ErrTruncated, m, len(rest))
+	}
+	ext, fixed, err := rsDecode(rest[:m], headerParity(size))
+	if err != nil {
+		return nil, nil, 0, true, fmt.Errorf("%w: header: %v", ErrCorrupted, err)
+	}
+	rest = append(append([]byte(nil), ext...), rest[m:]...)
+	return header, rest, corrected + fixed, true, nil
+}
+
+// ParseContainer - Đọc container từ dữ liệu trích xuất được.
+// Các byte thừa phía sau payload được bỏ qua.
+func ParseContainer(data []byte) (*Container, error) {
+	header, rest, headerCorrected, protected, err := decodeProtectedHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if !protected {
+		if len(data) < len(containerMagic) || data[0] != containerMagic[0] || data[1] != containerMagic[1] {
+			return nil, ErrNoHiddenData
+		}
+		if len(data) < ContainerHeaderSize {
+			return nil, fmt.Errorf("%w: header needs %d bytes, got %d", ErrTruncated, ContainerHeaderSize, len(data))
+		}
+		header, rest = data[:ContainerHeaderSize], data[ContainerHeaderSize:]
+	}
+
+	c := &Container{
+		Version:   header[2],
+		MethodID:  header[3],
+		Flags:     Flags(header[4]),
+		Corrected: headerCorrected,
+	}
+	if c.Version == 0 || c.Version > ContainerVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, c.Version)
+	}
+	if c.Flags&^knownFlags != 0 {
+		return nil, fmt.Errorf("%w: unknown flags 0x%02x", ErrCorrupted, uint8(c.Flags&^knownFlags))
+	}
+	// Header có ECC nhưng không sửa được thì các trường còn lại không đáng tin
+	if !protected && c.Flags.Has(FlagECC) && c.Version >= protectedHeaderVersion {
+		return nil, fmt.Errorf("%w: header has too many errors to repair", ErrCorrupted)
+	}
+
+	length := binary.BigEndian.Uint32(header[5:9])
+	sum := binary.BigEndian.Uint32(header[9:13])
+
+	extStart := rest
+	if c.Flags.Has(FlagEncrypted) {
+		if len(rest) < kdfParamsSize {
+			return nil, fmt.Errorf("%w: encryption header needs %d bytes, got %d", ErrTruncated, kdfParamsSize, len(rest))
+		}
+		c.KDF = parseKDFParams(rest[:kdfParamsSize])
+		rest = rest[kdfParamsSize:]
+	}
+	if c.Flags.Has(FlagECC) {
+		if len(rest) < eccParamsSize {
+			return nil, fmt.Errorf("%w: ecc header needs %d bytes, got %d", ErrTruncated, eccParamsSize, len(rest))
+		}
+		params, err := parseECCParams(rest[:eccParamsSize])
+		if err != nil {
+			return nil, err
+		}
+		c.ECC = params
+		rest = rest[eccParamsSize:]
+	}
+	if c.Flags.Has(FlagShare) {
+		if len(rest) < shareParamsSize {
+			return nil, fmt.Errorf("%w: share header needs %d bytes, got %d", ErrTruncated, shareParamsSize, len(rest))
+		}
+		params, err := parseShareParams(rest[:shareParamsSize])
+		if err != nil {
+			return nil, err
+		}
+		c.Share = params
+		rest = rest[shareParamsSize:]
+	}
+	ext := extStart[:len(extStart)-len(rest)]
+
+	if uint64(len(rest)) < uint64(length) {
+		return nil, fmt.Errorf("%w: payload needs %d bytes, got %d", ErrTruncated, length, len(rest))
+	}
+	c.Payload = rest[:length]
+	if c.Flags.Has(FlagECC) {
+		payload, corrected, err := c.ECC.decode(c.Payload)
+		if err != nil {
+			return nil, err
+		}
+		c.Payload = payload
+		c.Corrected += corrected
+	}
+	if checksum(ext, c.Payload) != sum {
+		return nil, ErrCorrupted
+	}
+	return c, nil
+}
+
+// checksum - CRC-32 của phần mở rộng header và payload
+func checksum(ext, payload []byte) uint32 {
+	sum := crc32.ChecksumIEEE(ext)
+	return crc32.Update(sum, crc32.IEEETable, payload)
+}
@@ -0,0 +1,196 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"unicode/utf8"
+)
+
+// Giới hạn khi sinh văn bản phủ
+const (
+	// DefaultCoverCharacters - Độ dài mặc định khi request không yêu cầu độ dài hay payload
+	DefaultCoverCharacters = 500
+	// MaxCoverCharacters - Độ dài tối đa của văn bản phủ được sinh
+	MaxCoverCharacters = 100000
+	// maxSentenceTokens - Câu dài quá số token này được kết thúc bằng dấu chấm
+	maxSentenceTokens = 60
+	// sentencesPerParagraph - Số câu tối đa của một đoạn văn
+	sentencesPerParagraph = 6
+)
+
+// ErrCoverGenerationLimit - Không sinh được văn bản đủ dung lượng trong giới hạn độ dài
+var ErrCoverGenerationLimit = errors.New("cannot generate a cover large enough for the payload")
+
+// ErrStructuredCoverMethod - Phương pháp cần tài liệu có cấu trúc, không dùng được với văn bản sinh ra
+var ErrStructuredCoverMethod = errors.New("method needs a structured document and cannot hide data in generated prose")
+
+// CoverOptions - Tùy chọn sinh văn bản phủ
+type CoverOptions struct {
+	// Language - Mô hình đi kèm theo ngôn ngữ, bỏ qua khi có Corpus
+	Language string
+	// Corpus - Corpus do người dùng tải lên, mỗi dòng là một đoạn văn
+	Corpus string
+	// Characters - Độ dài tối thiểu (ký tự)
+	Characters int
+	// Method, Encrypt, ECC, PayloadSize - Văn bản được sinh tiếp cho đến khi đủ dung lượng
+	// để giấu payload bằng phương pháp đã chọn
+	Method      string
+	Encrypt     bool
+	ECC         *ECCOptions
+	PayloadSize int
+}
+
+// GeneratedCover - Văn bản phủ đã sinh và dung lượng của nó
+type GeneratedCover struct {
+	Text         string `json:"text"`
+	Characters   int    `json:"characters"`
+	Words        int    `json:"words"`
+	Method       string `json:"method"`
+	CapacityBits int    `json:"capacity_bits"`
+	RequiredBits int    `json:"required_bits,omitempty"`
+}
+
+// GenerateCover - Sinh văn bản phủ bằng chuỗi Markov trên mô hình n-gram, đủ dài và đủ
+// dung lượng cho payload với phương pháp đã chọn
+func (s *StegoService) GenerateCover(opts CoverOptions) (*GeneratedCover, error) {
+	m, err := s.getMethod(opts.Method)
+	if err != nil {
+		return nil, err
+	}
+	if m.Capabilities().Structured {
+		return nil, fmt.Errorf("%w: %s", ErrStructuredCoverMethod, m.Name())
+	}
+
+	var model *NgramModel
+	if opts.Corpus != "" {
+		model, err = TrainNgramModel(strings.NewReader(opts.Corpus), DefaultNgramOrder, maxNgramTopK)
+		if err != nil {
+			return nil, fmt.Errorf("invalid corpus: %w", err)
+		}
+	} else {
+		language := opts.Language
+		if language == "" {
+			language = "vi"
+		}
+		model, err = s.config.Ngrams.Get(language)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	characters := opts.Characters
+	if characters == 0 && opts.PayloadSize == 0 {
+		characters = DefaultCoverCharacters
+	}
+	if characters > MaxCoverCharacters {
+		return nil, fmt.Errorf("cover length must not exceed %d characters", MaxCoverCharacters)
+	}
+	requiredBits := 0
+	if opts.PayloadSize > 0 {
+		size, _, err := containerSize(opts.PayloadSize, 0, CapacityOptions{Encrypt: opts.Encrypt, ECC: opts.ECC})
+		if err != nil {
+			return nil, err
+		}
+		requiredBits = size * 8
+	}
+
+	// Dung lượng chỉ được tính lại khi đạt độ dài dự kiến, ngoại suy từ lần tính trước
+	checkAt := characters
+	text := markovText(model, func(length int, text func() string) bool {
+		if length < checkAt {
+			return false
+		}
+		if requiredBits == 0 {
+			return true
+		}
+		capacity := m.Capacity(text())
+		if capacity >= requiredBits {
+			return true
+		}
+		next := length * 2
+		if capacity > 0 {
+			next = length + (length*requiredBits/capacity-length)/2
+		}
+		checkAt = max(next, length+1)
+		return false
+	})
+	capacityBits := m.Capacity(text)
+	if capacityBits < requiredBits {
+		return nil, fmt.Errorf("%w with method %s: need %d bits, %d characters hold %d",
+			ErrCoverGenerationLimit, m.Name(), requiredBits, utf8.RuneCountInString(text), capacityBits)
+	}
+	return &GeneratedCover{
+		Text:         text,
+		Characters:   utf8.RuneCountInString(text),
+		Words:        len(strings.Fields(text)),
+		Method:       m.Name(),
+		CapacityBits: capacityBits,
+		RequiredBits: requiredBits,
+	}, nil
+}
+
+// markovText - Sinh từng câu theo chuỗi Markov (chọn token ngẫu nhiên theo số lần xuất hiện)
+// cho đến khi enough (nhận độ dài hiện tại tính theo ký tự) trả về true hoặc đạt MaxCoverCharacters
+func markovText(model *NgramModel, enough func(length int, text func() string) bool) string {
+	var paragraphs []string
+	var ids []uint32
+	history := make([]uint32, model.Order-1)
+	sentences, tokens := 0, 0
+	length := 0
+	for {
+		candidates := model.candidates(history)
+		id := pickNgramCandidate(candidates)
+		tokens++
+		ended := strings.Contains(".!?", model.tokens[id])
+		if tokens >= maxSentenceTokens && !ended {
+			// Corpus không có dấu chấm thì câu kết thúc không có dấu câu
+			if dot, ok := model.ids["."]; ok {
+				id = dot
+			}
+			ended = true
+		}
+		ids = append(ids, id)
+		if len(history) > 0 {
+			history = append(history[1:], id)
+		}
+		if !ended {
+			continue
+		}
+
+		sentences++
+		tokens = 0
+		paragraph := model.detokenize(ids)
+		text := func() string {
+			return strings.Join(append(paragraphs, paragraph), "\n")
+		}
+		total := length + utf8.RuneCountInString(paragraph)
+		if enough(total, text) || total >= MaxCoverCharacters {
+			return text()
+		}
+		if sentences == sentencesPerParagraph {
+			paragraphs = append(paragraphs, paragraph)
+			length += utf8.RuneCountInString(paragraph) + 1
+			ids = ids[:0]
+			history = make([]uint32, model.Order-1)
+			sentences = 0
+		}
+	}
+}
+
+// pickNgramCandidate - Chọn ngẫu nhiên một ứng viên với xác suất tỉ lệ số lần xuất hiện
+func pickNgramCandidate(candidates []ngramCandidate) uint32 {
+	var total uint64
+	for _, candidate := range candidates {
+		total += uint64(candidate.count)
+	}
+	target := rand.Uint64N(total)
+	for _, candidate := range candidates {
+		if target < uint64(candidate.count) {
+			return candidate.token
+		}
+		target -= uint64(candidate.count)
+	}
+	return candidates[len(candidates)-1].token
+}
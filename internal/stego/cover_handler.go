@@ -0,0 +1,53 @@
+package stego
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CoverGenerateRequest - Request body cho sinh văn bản phủ
+type CoverGenerateRequest struct {
+	// Language - Mô hình đi kèm theo ngôn ngữ ("vi" hoặc "en"), mặc định "vi"
+	Language string `json:"language"`
+	// Corpus - Corpus tự tải lên thay cho mô hình đi kèm, mỗi dòng là một đoạn văn
+	Corpus string `json:"corpus" binding:"max=2000000"`
+	// Characters - Độ dài tối thiểu của văn bản (ký tự)
+	Characters int `json:"characters" binding:"gte=0,lte=100000"`
+	// Method, Encrypt, ECC, PayloadSize - Sinh đủ dung lượng để giấu payload_size byte
+	Method      string      `json:"method"`
+	Encrypt     bool        `json:"encrypt"`
+	ECC         *ECCRequest `json:"ecc"`
+	PayloadSize int         `json:"payload_size" binding:"gte=0"`
+}
+
+// GenerateCover - Sinh văn bản phủ đủ dài và đủ dung lượng cho payload
+func (h *Handler) GenerateCover(c *gin.Context) {
+	var req CoverGenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	cover, err := h.service.GenerateCover(CoverOptions{
+		Language:    req.Language,
+		Corpus:      req.Corpus,
+		Characters:  req.Characters,
+		Method:      req.Method,
+		Encrypt:     req.Encrypt,
+		ECC:         req.ECC.options(),
+		PayloadSize: req.PayloadSize,
+	})
+	if errors.Is(err, ErrStructuredCoverMethod) {
+		utils.RespondWithValidationError(c, utils.FieldErrors{"method": err.Error()})
+		return
+	}
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, "Cover generated successfully", cover)
+}
@@ -0,0 +1,193 @@
+package stego
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// CipherAES256GCM - ID thuật toán mã hóa AES-256-GCM trong header
+const CipherAES256GCM uint8 = 1
+
+const (
+	// kdfParamsSize - Kích thước phần mở rộng mã hóa trong header
+	kdfParamsSize = 23
+	saltSize      = 16
+	keySize       = 32
+
+	// encryptionOverhead - Số byte mã hóa thêm vào payload (nonce + tag của GCM)
+	encryptionOverhead = 12 + 16
+
+	// Giới hạn tham số KDF khi trích xuất để tránh bị lợi dụng làm cạn tài nguyên
+	maxArgon2Time     = 16
+	maxArgon2MemoryKB = 1 << 20
+)
+
+// Các lỗi mã hóa
+var (
+	ErrPassphraseRequired = errors.New("hidden data is encrypted, passphrase is required")
+	ErrWrongPassphrase    = errors.New("wrong passphrase or tampered data")
+)
+
+// KDFConfig - Chi phí Argon2id dùng khi mã hóa
+type KDFConfig struct {
+	Time     uint32
+	MemoryKB uint32
+	Threads  uint8
+}
+
+// DefaultKDFConfig - Tham số Argon2id mặc định (theo khuyến nghị của RFC 9106)
+var DefaultKDFConfig = KDFConfig{
+	Time:     3,
+	MemoryKB: 64 * 1024,
+	Threads:  4,
+}
+
+// KDFParams - Tham số dẫn xuất khóa được lưu trong header container:
+//
+//	cipher    uint8
+//	time      uint8
+//	threads   uint8
+//	memory    uint32  (KiB)
+//	salt      [16]byte
+type KDFParams struct {
+	Cipher   uint8
+	Time     uint8
+	Threads  uint8
+	MemoryKB uint32
+	Salt     [saltSize]byte
+}
+
+// newKDFParams - Tạo tham số KDF với salt ngẫu nhiên
+func newKDFParams(config KDFConfig) (*KDFParams, error) {
+	if config.Time == 0 || config.Time > 255 || config.MemoryKB == 0 || config.Threads == 0 {
+		return nil, errors.New("invalid argon2 configuration")
+	}
+	params := &KDFParams{
+		Cipher:   CipherAES256GCM,
+		Time:     uint8(config.Time),
+		Threads:  config.Threads,
+		MemoryKB: config.MemoryKB,
+	}
+	if _, err := rand.Read(params.Salt[:]); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (p *KDFParams) marshal() []byte {
+	buf := make([]byte, kdfParamsSize)
+	buf[0] = p.Cipher
+	buf[1] = p.Time
+	buf[2] = p.Threads
+	binary.BigEndian.PutUint32(buf[3:7], p.MemoryKB)
+	copy(buf[7:], p.Salt[:])
+	return buf
+}
+
+func parseKDFParams(data []byte) *KDFParams {
+	p := &KDFParams{
+		Cipher:   data[0],
+		Time:     data[1],
+		Threads:  data[2],
+		MemoryKB: binary.BigEndian.Uint32(data[3:7]),
+	}
+	copy(p.Salt[:], data[7:kdfParamsSize])
+	return p
+}
+
+// deriveKey - Dẫn xuất khóa từ passphrase bằng Argon2id
+func (p *KDFParams) deriveKey(passphrase string) ([]byte, error) {
+	if p.Cipher != CipherAES256GCM {
+		return nil, fmt.Errorf("unsupported cipher %d", p.Cipher)
+	}
+	if p.Time == 0 || p.Time > maxArgon2Time || p.Threads == 0 || p.MemoryKB == 0 || p.MemoryKB > maxArgon2MemoryKB {
+		return nil, errors.New("argon2 parameters in hidden data are out of range")
+	}
+	return argon2.IDKey([]byte(passphrase), p.Salt[:], uint32(p.Time), p.MemoryKB, p.Threads, keySize), nil
+}
+
+// Tham số Argon2id cố định cho khóa vị trí (acrostic, rải bit): cùng khóa phải luôn cho
+// cùng kết quả nên không dùng tham số KDF trong cấu hình
+const (
+	positionKDFTime     = 3
+	positionKDFMemoryKB = 64 * 1024
+	positionKDFThreads  = 4
+)
+
+// derivePositionKey - Dẫn xuất khóa 32 byte từ secret, mỗi mục đích dùng một salt riêng
+func derivePositionKey(secret, purpose string) []byte {
+	salt := []byte("datahiding-" + purpose)
+	return argon2.IDKey([]byte(secret), salt, positionKDFTime, positionKDFMemoryKB, positionKDFThreads, keySize)
+}
+
+// deterministicSalt - Salt cố định theo phương pháp, dùng khi cùng đầu vào phải luôn cho cùng
+// ciphertext (văn bản sinh ra phải tái lập được). Salt nằm trong header nên không được phụ
+// thuộc trực tiếp vào passphrase: muốn thử passphrase vẫn phải chạy Argon2id, còn tính tất
+// định đến từ nonce dẫn xuất bằng khóa.
+func deterministicSalt(methodID uint8) [saltSize]byte {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("datahiding-deterministic-salt-%d", methodID)))
+	var salt [saltSize]byte
+	copy(salt[:], sum[:])
+	return salt
+}
+
+// encryptPayload - Mã hóa payload bằng AES-256-GCM, nonce được ghi trước ciphertext.
+// aad là phần header được xác thực cùng dữ liệu. Khi deterministic, nonce là HMAC của
+// aad và plaintext theo khóa đã dẫn xuất thay vì ngẫu nhiên.
+func encryptPayload(passphrase string, params *KDFParams, plaintext, aad []byte, deterministic bool) ([]byte, error) {
+	key, err := params.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if deterministic {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(aad)
+		mac.Write(plaintext)
+		copy(nonce, mac.Sum(nil))
+	} else if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// decryptPayload - Giải mã payload đã được mã hóa bởi encryptPayload
+func decryptPayload(passphrase string, params *KDFParams, ciphertext, aad []byte) ([]byte, error) {
+	key, err := params.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrWrongPassphrase
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, aad)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,512 @@
+package stego
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+)
+
+// Tên và ID của phương pháp giấu tin trong tài liệu DOCX
+const (
+	MethodDocx   = "docx"
+	methodIDDocx = 12
+)
+
+// Các kênh giấu tin trong WordprocessingML, đều không làm thay đổi nội dung hiển thị
+const (
+	// DocxChannelRsid - Mỗi đoạn văn mang 32 bit trong thuộc tính w:rsidR
+	DocxChannelRsid = "rsid"
+	// DocxChannelRuns - Mỗi nhóm run liền nhau cùng định dạng mang 1 bit:
+	// gộp thành một run là 0, tách thành hai run là 1
+	DocxChannelRuns = "runs"
+	// DocxChannelZeroWidth - Run chỉ chứa ký tự zero-width ở cuối mỗi đoạn văn
+	DocxChannelZeroWidth = "zero-width"
+	// DocxChannelCustomXML - Phần custom XML riêng trong gói, không hiển thị trong tài liệu
+	DocxChannelCustomXML = "custom-xml"
+)
+
+// DocxChannels - Các kênh được hỗ trợ, theo thứ tự thử khi trích xuất
+var DocxChannels = []string{DocxChannelRsid, DocxChannelRuns, DocxChannelZeroWidth, DocxChannelCustomXML}
+
+const (
+	// docxZeroWidthBytesPerParagraph - Số byte tối đa trong run zero-width của mỗi đoạn văn
+	docxZeroWidthBytesPerParagraph = 8
+	// docxCustomXMLBytes - Dung lượng của phần custom XML. Giới hạn vừa phải vì khi rải bit,
+	// toàn bộ dung lượng được điền dữ liệu ngẫu nhiên.
+	docxCustomXMLBytes = 64 << 10
+
+	docxContentType          = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	docxCustomXMLRelType     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml"
+	docxCustomXMLPropsType   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXmlProps"
+	docxCustomXMLPropsCT     = "application/vnd.openxmlformats-officedocument.customXmlProperties+xml"
+	docxCustomXMLRoot        = "<cache><data>"
+	docxCustomXMLRootEnd     = "</data></cache>"
+	docxZeroWidthRunTemplate = `<w:r><w:t xml:space="preserve">%s</w:t></w:r>`
+)
+
+// ErrUnknownDocxChannel - Kênh giấu tin không được hỗ trợ
+var ErrUnknownDocxChannel = errors.New("unknown docx channel")
+
+var (
+	docxParagraphStart = regexp.MustCompile(`<w:p(?:\s[^>]*)?>`)
+	docxRunStart       = regexp.MustCompile(`<w:r(?:\s[^>]*)?>`)
+	docxSimpleRunBody  = regexp.MustCompile(`(?s)^\s*(?:<w:rPr>(.*)</w:rPr>\s*)?<w:t(?:\s[^>]*)?>([^<]*)</w:t>\s*$`)
+	docxCustomXMLItem  = regexp.MustCompile(`^customXml/item(\d+)\.xml$`)
+)
+
+// Docx - Giấu dữ liệu trong tài liệu DOCX qua một kênh không hiển thị.
+// Văn bản phủ là nội dung nhị phân của file .docx, văn bản trả về là file .docx đã giấu tin,
+// nhờ vậy các lớp mã hóa, ECC và rải bit của service được dùng lại nguyên vẹn.
+type Docx struct {
+	Channel string
+}
+
+// NewDocx - Tạo phương pháp DOCX với một kênh, mặc định là rsid
+func NewDocx(channel string) (*Docx, error) {
+	if channel == "" {
+		channel = DocxChannelRsid
+	}
+	for _, c := range DocxChannels {
+		if c == channel {
+			return &Docx{Channel: channel}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownDocxChannel, channel)
+}
+
+// ID - ID phương pháp trong header container
+func (d *Docx) ID() uint8 {
+	return methodIDDocx
+}
+
+// Name - Tên phương pháp
+func (d *Docx) Name() string {
+	return MethodDocx
+}
+
+// Capabilities - Dữ liệu nằm trong cấu trúc tài liệu nên không bị ảnh hưởng bởi xử lý văn bản
+func (d *Docx) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: true,
+		Structured:            true,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong tài liệu, 0 nếu không đọc được tài liệu
+func (d *Docx) Capacity(cover string) int {
+	p, err := openDocx(cover)
+	if err != nil {
+		return 0
+	}
+	doc, err := p.read(p.main)
+	if err != nil {
+		return 0
+	}
+	switch d.Channel {
+	case DocxChannelRsid:
+		return len(docxParagraphStart.FindAllStringIndex(string(doc), -1)) * 32
+	case DocxChannelRuns:
+		return len(docxRunSlots(docxRunGroups(string(doc))))
+	case DocxChannelZeroWidth:
+		return strings.Count(stripDocxZeroWidth(string(doc)), "</w:p>") * docxZeroWidthBytesPerParagraph * 8
+	case DocxChannelCustomXML:
+		return docxCustomXMLBytes * 8
+	}
+	return 0
+}
+
+// Embed - Giấu data vào tài liệu, trả về nội dung file .docx mới
+func (d *Docx) Embed(cover string, data []byte) (string, error) {
+	p, err := openDocx(cover)
+	if err != nil {
+		return "", err
+	}
+	doc, err := p.read(p.main)
+	if err != nil {
+		return "", err
+	}
+	if len(data)*8 > d.Capacity(cover) {
+		return "", ErrCoverTooSmall
+	}
+
+	switch d.Channel {
+	case DocxChannelRsid:
+		p.write(p.main, []byte(embedDocxRsid(string(doc), data)))
+	case DocxChannelRuns:
+		p.write(p.main, []byte(embedDocxRuns(string(doc), bitutil.BytesToBits(data))))
+	case DocxChannelZeroWidth:
+		p.write(p.main, []byte(embedDocxZeroWidth(string(doc), data)))
+	case DocxChannelCustomXML:
+		if err := embedDocxCustomXML(p, data); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownDocxChannel, d.Channel)
+	}
+	return p.bytes()
+}
+
+// Extract - Lấy lại dữ liệu đã giấu trong tài liệu
+func (d *Docx) Extract(text string) ([]byte, error) {
+	p, err := openDocx(text)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := p.read(p.main)
+	if err != nil {
+		return nil, err
+	}
+
+	switch d.Channel {
+	case DocxChannelRsid:
+		return extractDocxRsid(string(doc)), nil
+	case DocxChannelRuns:
+		return bitutil.BitsToBytes(extractDocxRuns(string(doc))), nil
+	case DocxChannelZeroWidth:
+		return extractDocxZeroWidth(string(doc)), nil
+	case DocxChannelCustomXML:
+		return extractDocxCustomXML(p)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownDocxChannel, d.Channel)
+}
+
+// embedDocxRsid - Ghi mỗi 4 byte dữ liệu vào w:rsidR của một đoạn văn.
+// Các đoạn văn sau phần dữ liệu giữ nguyên rsid.
+func embedDocxRsid(doc string, data []byte) string {
+	i := 0
+	return docxParagraphStart.ReplaceAllStringFunc(doc, func(tag string) string {
+		if i >= len(data) {
+			return tag
+		}
+		var word [4]byte
+		i += copy(word[:], data[i:])
+		return setXMLAttr(tag, "w:rsidR", fmt.Sprintf("%08X", binary.BigEndian.Uint32(word[:])))
+	})
+}
+
+// extractDocxRsid - Đọc w:rsidR của mọi đoạn văn, đoạn văn không có rsid được tính là 0
+func extractDocxRsid(doc string) []byte {
+	tags := docxParagraphStart.FindAllString(doc, -1)
+	data := make([]byte, 0, len(tags)*4)
+	for _, tag := range tags {
+		value, _ := strconv.ParseUint(xmlAttr(tag, "w:rsidR"), 16, 32)
+		data = binary.BigEndian.AppendUint32(data, uint32(value))
+	}
+	return data
+}
+
+// docxRun - Một run chỉ chứa văn bản (có thể kèm định dạng) trong document.xml
+type docxRun struct {
+	start, end int
+	// tag - Thẻ mở <w:r ...>
+	tag string
+	// props - Nội dung của w:rPr, rỗng nếu không có
+	props string
+	// text - Văn bản đã unescape
+	text string
+}
+
+// docxRunGroups - Các nhóm run văn bản liền nhau có cùng định dạng, theo thứ tự tài liệu.
+// Run có nội dung khác (tab, ngắt dòng, hình ảnh...) không thuộc nhóm nào và ngăn cách
+// các nhóm; run lồng bên trong (ví dụ trong text box) vẫn được tìm thấy.
+func docxRunGroups(doc string) [][]docxRun {
+	var groups [][]docxRun
+	var last *docxRun
+	for pos := 0; ; {
+		loc := docxRunStart.FindStringIndex(doc[pos:])
+		if loc == nil {
+			break
+		}
+		start, bodyStart := pos+loc[0], pos+loc[1]
+		tag := doc[start:bodyStart]
+		pos = bodyStart
+		if strings.HasSuffix(tag, "/>") {
+			continue
+		}
+		end := strings.Index(doc[bodyStart:], "</w:r>")
+		if end < 0 {
+			break
+		}
+		m := docxSimpleRunBody.FindStringSubmatch(doc[bodyStart : bodyStart+end])
+		if m == nil {
+			continue
+		}
+		run := docxRun{
+			start: start,
+			end:   bodyStart + end + len("</w:r>"),
+			tag:   tag,
+			props: m[1],
+			text:  html.UnescapeString(m[2]),
+		}
+		pos = run.end
+		if last != nil && last.props == run.props && strings.TrimSpace(doc[last.end:run.start]) == "" {
+			groups[len(groups)-1] = append(groups[len(groups)-1], run)
+		} else {
+			groups = append(groups, []docxRun{run})
+		}
+		last = &groups[len(groups)-1][len(groups[len(groups)-1])-1]
+	}
+	return groups
+}
+
+// docxRunSlots - Các nhóm mang được bit: tổng văn bản có ít nhất 2 ký tự để tách được
+func docxRunSlots(groups [][]docxRun) [][]docxRun {
+	var slots [][]docxRun
+	for _, group := range groups {
+		if utf8.RuneCountInString(docxGroupText(group)) >= 2 {
+			slots = append(slots, group)
+		}
+	}
+	return slots
+}
+
+func docxGroupText(group []docxRun) string {
+	var sb strings.Builder
+	for _, run := range group {
+		sb.WriteString(run.text)
+	}
+	return sb.String()
+}
+
+// embedDocxRuns - Gộp mỗi nhóm mang bit thành một run (bit 0) hoặc hai run (bit 1).
+// Điểm tách là sau khoảng trắng đầu tiên nếu có, giống cách Word tự tách run khi sửa chữ.
+func embedDocxRuns(doc string, bits []byte) string {
+	slots := docxRunSlots(docxRunGroups(doc))
+	var sb strings.Builder
+	prev := 0
+	for i, group := range slots {
+		if i >= len(bits) {
+			break
+		}
+		first, last := group[0], group[len(group)-1]
+		sb.WriteString(doc[prev:first.start])
+		prev = last.end
+
+		text := []rune(docxGroupText(group))
+		if bits[i] == 0 {
+			sb.WriteString(docxRunXML(first, string(text)))
+			continue
+		}
+		split := len(text) / 2
+		if space := strings.IndexRune(string(text[:len(text)-1]), ' '); space >= 0 {
+			split = utf8.RuneCountInString(string(text)[:space]) + 1
+		}
+		sb.WriteString(docxRunXML(first, string(text[:split])))
+		sb.WriteString(docxRunXML(first, string(text[split:])))
+	}
+	sb.WriteString(doc[prev:])
+	return sb.String()
+}
+
+// docxRunXML - Ghi một run văn bản với thẻ mở và định dạng của run mẫu
+func docxRunXML(like docxRun, text string) string {
+	var sb strings.Builder
+	sb.WriteString(like.tag)
+	if like.props != "" {
+		sb.WriteString("<w:rPr>" + like.props + "</w:rPr>")
+	}
+	sb.WriteString(`<w:t xml:space="preserve">` + xmlEscape(text) + "</w:t></w:r>")
+	return sb.String()
+}
+
+// extractDocxRuns - Đọc bit từ số run của mỗi nhóm
+func extractDocxRuns(doc string) []byte {
+	slots := docxRunSlots(docxRunGroups(doc))
+	bits := make([]byte, len(slots))
+	for i, group := range slots {
+		if len(group) > 1 {
+			bits[i] = 1
+		}
+	}
+	return bits
+}
+
+// stripDocxZeroWidth - Xóa các run zero-width đã chèn từ lần giấu tin trước
+func stripDocxZeroWidth(doc string) string {
+	var sb strings.Builder
+	prev := 0
+	for _, group := range docxRunGroups(doc) {
+		for _, run := range group {
+			if isDocxZeroWidthRun(run) {
+				sb.WriteString(doc[prev:run.start])
+				prev = run.end
+			}
+		}
+	}
+	sb.WriteString(doc[prev:])
+	return sb.String()
+}
+
+func isDocxZeroWidthRun(run docxRun) bool {
+	if run.text == "" || run.props != "" {
+		return false
+	}
+	for _, r := range run.text {
+		if !isZeroWidth(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// embedDocxZeroWidth - Chèn run zero-width vào cuối mỗi đoạn văn, mỗi run tối đa
+// docxZeroWidthBytesPerParagraph byte
+func embedDocxZeroWidth(doc string, data []byte) string {
+	doc = stripDocxZeroWidth(doc)
+	var sb strings.Builder
+	for len(data) > 0 {
+		end := strings.Index(doc, "</w:p>")
+		if end < 0 {
+			break
+		}
+		n := min(docxZeroWidthBytesPerParagraph, len(data))
+		var chars strings.Builder
+		for _, b := range data[:n] {
+			for shift := 6; shift >= 0; shift -= 2 {
+				chars.WriteRune(zeroWidthAlphabet[(b>>uint(shift))&0x03])
+			}
+		}
+		data = data[n:]
+		sb.WriteString(doc[:end])
+		sb.WriteString(fmt.Sprintf(docxZeroWidthRunTemplate, chars.String()))
+		sb.WriteString("</w:p>")
+		doc = doc[end+len("</w:p>"):]
+	}
+	sb.WriteString(doc)
+	return sb.String()
+}
+
+// extractDocxZeroWidth - Đọc dữ liệu từ các run zero-width theo thứ tự tài liệu
+func extractDocxZeroWidth(doc string) []byte {
+	var data []byte
+	for _, group := range docxRunGroups(doc) {
+		for _, run := range group {
+			if !isDocxZeroWidthRun(run) {
+				continue
+			}
+			chars := []rune(run.text)
+			if len(chars)%4 != 0 {
+				chars = padZeroWidth(chars, len(chars)+4-len(chars)%4)
+			}
+			for j := 0; j < len(chars); j += 4 {
+				var b byte
+				for _, r := range chars[j : j+4] {
+					b = b<<2 | zeroWidthValue(r)
+				}
+				data = append(data, b)
+			}
+		}
+	}
+	return data
+}
+
+// docxCustomXMLPart - Tìm phần custom XML đã giấu tin, rỗng nếu chưa có
+func docxCustomXMLPart(p *docxPackage) (string, error) {
+	for _, name := range p.names() {
+		if !docxCustomXMLItem.MatchString(name) {
+			continue
+		}
+		content, err := p.read(name)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(string(content), docxCustomXMLRoot) {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// embedDocxCustomXML - Ghi dữ liệu (base64) vào phần custom XML, tạo phần mới cùng
+// itemProps và các quan hệ nếu tài liệu chưa có
+func embedDocxCustomXML(p *docxPackage, data []byte) error {
+	content := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		docxCustomXMLRoot + base64.StdEncoding.EncodeToString(data) + docxCustomXMLRootEnd)
+	name, err := docxCustomXMLPart(p)
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		p.write(name, content)
+		return nil
+	}
+
+	n := 1
+	for p.has(fmt.Sprintf("customXml/item%d.xml", n)) || p.has(fmt.Sprintf("customXml/itemProps%d.xml", n)) {
+		n++
+	}
+	name = fmt.Sprintf("customXml/item%d.xml", n)
+	props := fmt.Sprintf("customXml/itemProps%d.xml", n)
+
+	var guid [16]byte
+	if _, err := rand.Read(guid[:]); err != nil {
+		return err
+	}
+	guid[6] = guid[6]&0x0f | 0x40
+	guid[8] = guid[8]&0x3f | 0x80
+	p.write(name, content)
+	p.write(props, []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+"\n"+
+		`<ds:datastoreItem ds:itemID="{%X-%X-%X-%X-%X}" xmlns:ds="http://schemas.openxmlformats.org/officeDocument/2006/customXml"><ds:schemaRefs/></ds:datastoreItem>`,
+		guid[0:4], guid[4:6], guid[6:8], guid[8:10], guid[10:16])))
+
+	if err := p.addRelationship(name, docxCustomXMLPropsType, props); err != nil {
+		return err
+	}
+	if err := p.addRelationship(p.main, docxCustomXMLRelType, name); err != nil {
+		return err
+	}
+	if err := p.addContentType(props, docxCustomXMLPropsCT); err != nil {
+		return err
+	}
+	hasXML, err := p.hasDefaultContentType("xml")
+	if err != nil {
+		return err
+	}
+	if !hasXML {
+		return p.addContentType(name, "application/xml")
+	}
+	return nil
+}
+
+// extractDocxCustomXML - Đọc dữ liệu từ phần custom XML đã giấu tin
+func extractDocxCustomXML(p *docxPackage) ([]byte, error) {
+	name, err := docxCustomXMLPart(p)
+	if err != nil || name == "" {
+		return nil, err
+	}
+	content, err := p.read(name)
+	if err != nil {
+		return nil, err
+	}
+	s := string(content)
+	start := strings.Index(s, docxCustomXMLRoot) + len(docxCustomXMLRoot)
+	end := strings.Index(s[start:], docxCustomXMLRootEnd)
+	if end < 0 {
+		return nil, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s[start : start+end]))
+	if err != nil {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// docxFileName - Tên file .docx trả về, giữ tên gốc nếu có
+func docxFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "document.docx"
+	}
+	return name
+}
@@ -0,0 +1,105 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// DocxEmbedRequest - Request multipart cho giấu message trong file .docx
+type DocxEmbedRequest struct {
+	File    *multipart.FileHeader `form:"file" json:"file" binding:"required"`
+	Message string                `form:"message" json:"message" binding:"required"`
+	// Channel - Kênh giấu tin, mặc định "rsid"
+	Channel string `form:"channel" json:"channel" binding:"omitempty,oneof=rsid runs zero-width custom-xml"`
+	EmbedFormFields
+}
+
+// DocxExtractRequest - Request multipart cho trích xuất message từ file .docx
+type DocxExtractRequest struct {
+	File *multipart.FileHeader `form:"file" json:"file" binding:"required"`
+	// Channel - Để trống để thử lần lượt các kênh
+	Channel    string `form:"channel" json:"channel" binding:"omitempty,oneof=rsid runs zero-width custom-xml"`
+	Passphrase string `form:"passphrase" json:"passphrase"`
+	Scatter    bool   `form:"scatter" json:"scatter"`
+	StegoKey   string `form:"stego_key" json:"stego_key" binding:"required_if=Scatter true Passphrase ''"`
+}
+
+// DocxEmbed - Giấu message vào file .docx tải lên và trả về tài liệu đã giấu tin
+func (h *Handler) DocxEmbed(c *gin.Context) {
+	var req DocxEmbedRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+	doc, ok := readUpload(c, req.File, MaxDocxSize, fmt.Sprintf("file must not exceed %d bytes", MaxDocxSize))
+	if !ok {
+		return
+	}
+
+	method, err := NewDocx(req.Channel)
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	out, err := h.service.EmbedWithMethod(method, string(doc), req.Message, req.options(MethodDocx))
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": docxFileName(req.File.Filename),
+	}))
+	c.Data(http.StatusOK, docxContentType, []byte(out))
+}
+
+// DocxExtract - Trích xuất message từ file .docx tải lên
+func (h *Handler) DocxExtract(c *gin.Context) {
+	var req DocxExtractRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+	doc, ok := readUpload(c, req.File, MaxDocxSize, fmt.Sprintf("file must not exceed %d bytes", MaxDocxSize))
+	if !ok {
+		return
+	}
+
+	channels := DocxChannels
+	if req.Channel != "" {
+		channels = []string{req.Channel}
+	}
+	opts := ExtractOptions{
+		Method:     MethodDocx,
+		Passphrase: req.Passphrase,
+		ScatterKey: scatterKey(req.Scatter, req.StegoKey, req.Passphrase),
+	}
+	// Lỗi cụ thể nhất được ưu tiên như khi tự nhận diện phương pháp
+	var result *ExtractResult
+	lastErr := ErrNoHiddenData
+	for _, channel := range channels {
+		method, err := NewDocx(channel)
+		if err != nil {
+			lastErr = err
+			break
+		}
+		result, err = h.service.ExtractWithMethod(method, string(doc), opts)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrNoHiddenData) {
+			lastErr = err
+		}
+	}
+	if result == nil {
+		respondWithStegoError(c, lastErr)
+		return
+	}
+	respondExtracted(c, result, nil)
+}
@@ -0,0 +1,295 @@
+package stego
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Giới hạn khi đọc file DOCX
+const (
+	// MaxDocxSize - Kích thước tối đa của file .docx tải lên
+	MaxDocxSize = 20 << 20
+	// maxDocxPartSize - Kích thước tối đa của một phần sau khi giải nén, tránh zip bomb
+	maxDocxPartSize = 64 << 20
+)
+
+// ErrInvalidDocx - File không phải tài liệu .docx hợp lệ
+var ErrInvalidDocx = errors.New("file is not a valid .docx document")
+
+var (
+	docxRelationshipPattern = regexp.MustCompile(`<Relationship\s[^>]*>`)
+	docxRelationshipID      = regexp.MustCompile(`\sId="rId(\d+)"`)
+	docxDefaultTypePattern  = regexp.MustCompile(`<Default\s[^>]*>`)
+
+	// xmlAttrPatterns - Biểu thức tìm thuộc tính theo tên, được biên dịch một lần
+	xmlAttrPatterns sync.Map
+)
+
+// docxPackage - Gói OPC (file zip) của tài liệu DOCX. Các phần được sửa hoặc thêm được
+// ghi lại khi đóng gói, các phần còn lại được sao chép nguyên dạng nén.
+type docxPackage struct {
+	files []*zip.File
+	// parts - Nội dung các phần đã đọc, đã sửa hoặc mới thêm
+	parts   map[string][]byte
+	changed map[string]bool
+	added   []string
+	// main - Đường dẫn phần tài liệu chính (thường là word/document.xml)
+	main string
+}
+
+// openDocx - Mở gói DOCX từ nội dung file
+func openDocx(data string) (*docxPackage, error) {
+	if len(data) > MaxDocxSize {
+		return nil, fmt.Errorf("%w: file exceeds %d bytes", ErrInvalidDocx, MaxDocxSize)
+	}
+	r, err := zip.NewReader(strings.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocx, err)
+	}
+	p := &docxPackage{
+		files:   r.File,
+		parts:   make(map[string][]byte),
+		changed: make(map[string]bool),
+	}
+
+	rels, err := p.read("_rels/.rels")
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range docxRelationshipPattern.FindAllString(string(rels), -1) {
+		if strings.HasSuffix(xmlAttr(rel, "Type"), "/officeDocument") {
+			p.main = strings.TrimPrefix(xmlAttr(rel, "Target"), "/")
+			break
+		}
+	}
+	if p.main == "" || !p.has(p.main) {
+		return nil, fmt.Errorf("%w: main document part not found", ErrInvalidDocx)
+	}
+	return p, nil
+}
+
+// has - Gói có phần name không
+func (p *docxPackage) has(name string) bool {
+	if _, ok := p.parts[name]; ok {
+		return true
+	}
+	return p.file(name) != nil
+}
+
+func (p *docxPackage) file(name string) *zip.File {
+	for _, f := range p.files {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// read - Đọc nội dung một phần
+func (p *docxPackage) read(name string) ([]byte, error) {
+	if content, ok := p.parts[name]; ok {
+		return content, nil
+	}
+	f := p.file(name)
+	if f == nil {
+		return nil, fmt.Errorf("%w: missing part %s", ErrInvalidDocx, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocx, err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(io.LimitReader(rc, maxDocxPartSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocx, err)
+	}
+	if len(content) > maxDocxPartSize {
+		return nil, fmt.Errorf("%w: part %s is too large", ErrInvalidDocx, name)
+	}
+	p.parts[name] = content
+	return content, nil
+}
+
+// write - Ghi nội dung một phần, thêm phần mới nếu chưa có
+func (p *docxPackage) write(name string, content []byte) {
+	if !p.has(name) {
+		p.added = append(p.added, name)
+	}
+	p.parts[name] = content
+	p.changed[name] = true
+}
+
+// names - Tên các phần theo thứ tự trong gói
+func (p *docxPackage) names() []string {
+	names := make([]string, 0, len(p.files)+len(p.added))
+	for _, f := range p.files {
+		names = append(names, f.Name)
+	}
+	return append(names, p.added...)
+}
+
+// bytes - Đóng gói lại thành file .docx, giữ nguyên thứ tự các phần
+func (p *docxPackage) bytes() (string, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range p.files {
+		if !p.changed[f.Name] {
+			if err := w.Copy(f); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if err := p.writePart(w, f.Name, f.Modified); err != nil {
+			return "", err
+		}
+	}
+	// Phần mới lấy thời gian sửa của phần đầu tiên để không lộ thời điểm giấu tin
+	modified := p.files[0].Modified
+	for _, name := range p.added {
+		if err := p.writePart(w, name, modified); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (p *docxPackage) writePart(w *zip.Writer, name string, modified time.Time) error {
+	part, err := w.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(p.parts[name])
+	return err
+}
+
+// relationshipsPath - Đường dẫn file quan hệ của một phần, ví dụ word/_rels/document.xml.rels
+func relationshipsPath(part string) string {
+	return path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+}
+
+// addRelationship - Thêm quan hệ từ phần source tới target (đường dẫn tuyệt đối trong gói)
+func (p *docxPackage) addRelationship(source, relType, target string) error {
+	relsPath := relationshipsPath(source)
+	rels := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`)
+	if p.has(relsPath) {
+		var err error
+		if rels, err = p.read(relsPath); err != nil {
+			return err
+		}
+	}
+
+	next := 1
+	for _, m := range docxRelationshipID.FindAllStringSubmatch(string(rels), -1) {
+		if id, err := strconv.Atoi(m[1]); err == nil && id >= next {
+			next = id + 1
+		}
+	}
+	// Target tương đối với thư mục của phần nguồn
+	relative := target
+	if dir := path.Dir(source); dir != "." {
+		relative = strings.Repeat("../", strings.Count(dir, "/")+1) + target
+	}
+	rel := fmt.Sprintf(`<Relationship Id="rId%d" Type="%s" Target="%s"/>`, next, relType, relative)
+	content, ok := insertBefore(string(rels), "</Relationships>", rel)
+	if !ok {
+		return fmt.Errorf("%w: malformed %s", ErrInvalidDocx, relsPath)
+	}
+	p.write(relsPath, []byte(content))
+	return nil
+}
+
+// addContentType - Khai báo kiểu nội dung cho một phần trong [Content_Types].xml
+func (p *docxPackage) addContentType(part, contentType string) error {
+	const name = "[Content_Types].xml"
+	types, err := p.read(name)
+	if err != nil {
+		return err
+	}
+	override := fmt.Sprintf(`<Override PartName="/%s" ContentType="%s"/>`, part, contentType)
+	content, ok := insertBefore(string(types), "</Types>", override)
+	if !ok {
+		return fmt.Errorf("%w: malformed %s", ErrInvalidDocx, name)
+	}
+	p.write(name, []byte(content))
+	return nil
+}
+
+// hasDefaultContentType - Phần mở rộng đã có kiểu nội dung mặc định chưa
+func (p *docxPackage) hasDefaultContentType(extension string) (bool, error) {
+	types, err := p.read("[Content_Types].xml")
+	if err != nil {
+		return false, err
+	}
+	for _, tag := range docxDefaultTypePattern.FindAllString(string(types), -1) {
+		if strings.EqualFold(xmlAttr(tag, "Extension"), extension) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// insertBefore - Chèn s vào trước lần xuất hiện cuối cùng của end
+func insertBefore(content, end, s string) (string, bool) {
+	i := strings.LastIndex(content, end)
+	if i < 0 {
+		return content, false
+	}
+	return content[:i] + s + content[i:], true
+}
+
+// xmlAttr - Giá trị (đã unescape) của thuộc tính trong thẻ mở, rỗng nếu không có
+func xmlAttr(tag, name string) string {
+	m := xmlAttrPattern(name).FindStringSubmatch(tag)
+	if m == nil {
+		return ""
+	}
+	return html.UnescapeString(m[1] + m[2])
+}
+
+// setXMLAttr - Đặt giá trị thuộc tính trong thẻ mở, thêm thuộc tính nếu chưa có
+func setXMLAttr(tag, name, value string) string {
+	attr := fmt.Sprintf(` %s="%s"`, name, xmlEscape(value))
+	if loc := xmlAttrPattern(name).FindStringIndex(tag); loc != nil {
+		return tag[:loc[0]] + attr + tag[loc[1]:]
+	}
+	end := len(tag) - 1
+	if strings.HasSuffix(tag, "/>") {
+		end--
+	}
+	return tag[:end] + attr + tag[end:]
+}
+
+func xmlAttrPattern(name string) *regexp.Regexp {
+	if re, ok := xmlAttrPatterns.Load(name); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	xmlAttrPatterns.Store(name, re)
+	return re
+}
+
+// xmlEscape - Escape văn bản để ghi vào nội dung hoặc thuộc tính XML
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,197 @@
+package stego
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Giá trị mặc định cho lớp sửa lỗi
+const (
+	DefaultECCParityRatio = 0.5
+	DefaultECCInterleave  = 4
+
+	maxECCParityRatio = 4
+	maxECCInterleave  = 64
+
+	// eccParamsSize - Kích thước phần mở rộng ECC trong header
+	eccParamsSize = 7
+)
+
+// ECCOptions - Tùy chọn mã sửa lỗi Reed-Solomon
+type ECCOptions struct {
+	// ParityRatio - Số byte parity trên mỗi byte dữ liệu
+	ParityRatio float64
+	// Interleave - Số codeword được đan xen với nhau để phân tán lỗi liên tiếp
+	Interleave int
+}
+
+// ECCParams - Tham số ECC được lưu trong header container:
+//
+//	data_size   uint8   số byte dữ liệu mỗi codeword
+//	parity      uint8   số byte parity mỗi codeword
+//	interleave  uint8   độ sâu đan xen
+//	length      uint32  độ dài payload trước khi mã hóa sửa lỗi
+type ECCParams struct {
+	DataSize   uint8
+	Parity     uint8
+	Interleave uint8
+	Length     uint32
+}
+
+// newECCParams - Chọn kích thước codeword phù hợp với độ dài payload
+func newECCParams(opts ECCOptions, length int) (*ECCParams, error) {
+	ratio := opts.ParityRatio
+	if ratio == 0 {
+		ratio = DefaultECCParityRatio
+	}
+	depth := opts.Interleave
+	if depth == 0 {
+		depth = DefaultECCInterleave
+	}
+	if ratio < 0 || ratio > maxECCParityRatio {
+		return nil, fmt.Errorf("parity ratio must be between 0 and %d", maxECCParityRatio)
+	}
+	if depth < 1 || depth > maxECCInterleave {
+		return nil, fmt.Errorf("interleave must be between 1 and %d", maxECCInterleave)
+	}
+	if uint64(length) > uint64(^uint32(0)) {
+		return nil, errors.New("payload is too large")
+	}
+
+	// Chia payload thành ít nhất depth codeword để việc đan xen có tác dụng,
+	// mỗi codeword không vượt quá 255 symbol
+	maxData := int(math.Floor(254 / (1 + ratio)))
+	blocks := (length + maxData - 1) / maxData
+	if blocks < depth {
+		blocks = depth
+	}
+	if blocks > length {
+		blocks = length
+	}
+	dataSize := 1
+	if blocks > 0 {
+		dataSize = (length + blocks - 1) / blocks
+	}
+	parity := int(math.Ceil(float64(dataSize) * ratio))
+	if parity < 2 {
+		parity = 2
+	}
+	if dataSize+parity > 255 {
+		parity = 255 - dataSize
+	}
+
+	return &ECCParams{
+		DataSize:   uint8(dataSize),
+		Parity:     uint8(parity),
+		Interleave: uint8(depth),
+		Length:     uint32(length),
+	}, nil
+}
+
+func (p *ECCParams) marshal() []byte {
+	buf := make([]byte, eccParamsSize)
+	buf[0] = p.DataSize
+	buf[1] = p.Parity
+	buf[2] = p.Interleave
+	binary.BigEndian.PutUint32(buf[3:7], p.Length)
+	return buf
+}
+
+func parseECCParams(data []byte) (*ECCParams, error) {
+	p := &ECCParams{
+		DataSize:   data[0],
+		Parity:     data[1],
+		Interleave: data[2],
+		Length:     binary.BigEndian.Uint32(data[3:7]),
+	}
+	if p.DataSize == 0 || p.Parity == 0 || p.Interleave == 0 || int(p.DataSize)+int(p.Parity) > 255 {
+		return nil, fmt.Errorf("%w: invalid ecc parameters", ErrCorrupted)
+	}
+	return p, nil
+}
+
+func (p *ECCParams) blocks() int {
+	return (int(p.Length) + int(p.DataSize) - 1) / int(p.DataSize)
+}
+
+// EncodedSize - Độ dài payload sau khi thêm parity
+func (p *ECCParams) EncodedSize() int {
+	return p.blocks() * (int(p.DataSize) + int(p.Parity))
+}
+
+// encode - Thêm parity Reed-Solomon và đan xen các codeword
+func (p *ECCParams) encode(data []byte) []byte {
+	k := int(p.DataSize)
+	padded := make([]byte, p.blocks()*k)
+	copy(padded, data)
+
+	codewords := make([][]byte, p.blocks())
+	for i := range codewords {
+		codewords[i] = rsEncode(padded[i*k:(i+1)*k], int(p.Parity))
+	}
+	return p.interleave(codewords)
+}
+
+// decode - Bỏ đan xen, sửa lỗi và trả về payload cùng số symbol đã sửa
+func (p *ECCParams) decode(body []byte) ([]byte, int, error) {
+	if len(body) < p.EncodedSize() {
+		return nil, 0, fmt.Errorf("%w: ecc body needs %d bytes, got %d", ErrTruncated, p.EncodedSize(), len(body))
+	}
+	codewords := p.deinterleave(body[:p.EncodedSize()])
+
+	data := make([]byte, 0, p.blocks()*int(p.DataSize))
+	corrected := 0
+	for i, cw := range codewords {
+		block, n, err := rsDecode(cw, int(p.Parity))
+		if err != nil {
+			return nil, corrected, fmt.Errorf("%w: codeword %d: %v", ErrCorrupted, i, err)
+		}
+		corrected += n
+		data = append(data, block...)
+	}
+	return data[:p.Length], corrected, nil
+}
+
+// interleave - Ghi các codeword theo từng nhóm Interleave codeword, lần lượt từng cột
+func (p *ECCParams) interleave(codewords [][]byte) []byte {
+	n := int(p.DataSize) + int(p.Parity)
+	depth := int(p.Interleave)
+	out := make([]byte, 0, len(codewords)*n)
+	for g := 0; g < len(codewords); g += depth {
+		end := g + depth
+		if end > len(codewords) {
+			end = len(codewords)
+		}
+		for j := 0; j < n; j++ {
+			for _, cw := range codewords[g:end] {
+				out = append(out, cw[j])
+			}
+		}
+	}
+	return out
+}
+
+func (p *ECCParams) deinterleave(body []byte) [][]byte {
+	n := int(p.DataSize) + int(p.Parity)
+	depth := int(p.Interleave)
+	codewords := make([][]byte, p.blocks())
+	for i := range codewords {
+		codewords[i] = make([]byte, n)
+	}
+	pos := 0
+	for g := 0; g < len(codewords); g += depth {
+		end := g + depth
+		if end > len(codewords) {
+			end = len(codewords)
+		}
+		for j := 0; j < n; j++ {
+			for _, cw := range codewords[g:end] {
+				cw[j] = body[pos]
+				pos++
+			}
+		}
+	}
+	return codewords
+}
@@ -0,0 +1,115 @@
+package stego
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// Giới hạn của file được giấu
+const (
+	// MaxHiddenFileSize - Kích thước tối đa của file được giấu (byte)
+	MaxHiddenFileSize = 1 << 20
+	// maxHiddenFileName - Độ dài tối đa của tên file (byte)
+	maxHiddenFileName = 255
+	// maxHiddenFileMIME - Độ dài tối đa của kiểu MIME (byte)
+	maxHiddenFileMIME = 255
+)
+
+// ErrHiddenFileTooLarge - File vượt quá kích thước cho phép
+var ErrHiddenFileTooLarge = fmt.Errorf("file must not exceed %d bytes", MaxHiddenFileSize)
+
+// HiddenFile - File được giấu kèm metadata
+type HiddenFile struct {
+	Name string
+	// MIME - Kiểu MIME được nhận diện từ nội dung file khi giấu
+	MIME string
+	Size int
+	Data []byte
+}
+
+// NewHiddenFile - Tạo file cần giấu, kiểu MIME được nhận diện từ nội dung thay vì tin
+// vào thông tin do client gửi. Tên file chỉ giữ phần tên cuối cùng.
+func NewHiddenFile(name string, data []byte) (*HiddenFile, error) {
+	if len(data) > MaxHiddenFileSize {
+		return nil, ErrHiddenFileTooLarge
+	}
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		name = ""
+	}
+	if len(name) > maxHiddenFileName {
+		name = truncateUTF8(name, maxHiddenFileName)
+	}
+	return &HiddenFile{
+		Name: name,
+		MIME: mimetype.Detect(data).String(),
+		Size: len(data),
+		Data: data,
+	}, nil
+}
+
+// marshalHiddenFile - Ghi metadata và nội dung file thành payload:
+//
+//	name  uvarint độ dài + UTF-8
+//	mime  uvarint độ dài + ASCII
+//	size  uvarint
+//	data  [size]byte
+func marshalHiddenFile(f *HiddenFile) []byte {
+	buf := make([]byte, 0, 3*binary.MaxVarintLen32+len(f.Name)+len(f.MIME)+len(f.Data))
+	buf = binary.AppendUvarint(buf, uint64(len(f.Name)))
+	buf = append(buf, f.Name...)
+	buf = binary.AppendUvarint(buf, uint64(len(f.MIME)))
+	buf = append(buf, f.MIME...)
+	buf = binary.AppendUvarint(buf, uint64(len(f.Data)))
+	return append(buf, f.Data...)
+}
+
+// unmarshalHiddenFile - Đọc file từ payload đã giải nén và giải mã
+func unmarshalHiddenFile(payload []byte) (*HiddenFile, error) {
+	f := &HiddenFile{}
+	rest := payload
+	field := func(limit int) ([]byte, error) {
+		n, size := binary.Uvarint(rest)
+		if size <= 0 || n > uint64(limit) || n > uint64(len(rest)-size) {
+			return nil, errors.New("invalid file metadata")
+		}
+		value := rest[size : size+int(n)]
+		rest = rest[size+int(n):]
+		return value, nil
+	}
+
+	name, err := field(maxHiddenFileName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	mime, err := field(maxHiddenFileMIME)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	data, err := field(MaxHiddenFileSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	if !utf8.Valid(name) {
+		return nil, fmt.Errorf("%w: file name is not valid UTF-8", ErrCorrupted)
+	}
+	f.Name = string(name)
+	f.MIME = string(mime)
+	f.Size = len(data)
+	f.Data = data
+	return f, nil
+}
+
+// truncateUTF8 - Cắt chuỗi còn tối đa n byte mà không cắt đôi ký tự
+func truncateUTF8(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
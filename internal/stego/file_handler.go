@@ -0,0 +1,157 @@
+package stego
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+)
+
+// EmbedFormFields - Các tùy chọn giấu tin chung của các request multipart. ECC được bật
+// bằng trường ecc, parity_ratio và interleave để trống thì dùng giá trị mặc định.
+type EmbedFormFields struct {
+	Passphrase  string  `form:"passphrase" json:"passphrase"`
+	ECC         bool    `form:"ecc" json:"ecc"`
+	ParityRatio float64 `form:"parity_ratio" json:"parity_ratio" binding:"omitempty,gt=0,lte=4"`
+	Interleave  int     `form:"interleave" json:"interleave" binding:"omitempty,gte=1,lte=64"`
+	Scatter     bool    `form:"scatter" json:"scatter"`
+	StegoKey    string  `form:"stego_key" json:"stego_key" binding:"required_if=Scatter true Passphrase ''"`
+}
+
+// options - Chuyển sang tùy chọn của service
+func (f *EmbedFormFields) options(method string) EmbedOptions {
+	opts := EmbedOptions{
+		Method:     method,
+		Passphrase: f.Passphrase,
+		ScatterKey: scatterKey(f.Scatter, f.StegoKey, f.Passphrase),
+	}
+	if f.ECC {
+		opts.ECC = &ECCOptions{
+			ParityRatio: f.ParityRatio,
+			Interleave:  f.Interleave,
+		}
+	}
+	return opts
+}
+
+// FileEmbedRequest - Request multipart cho giấu file
+type FileEmbedRequest struct {
+	Method string                `form:"method" json:"method"`
+	Cover  string                `form:"cover" json:"cover" binding:"required"`
+	File   *multipart.FileHeader `form:"file" json:"file" binding:"required"`
+	EmbedFormFields
+}
+
+// FileExtractRequest - Request body cho trích xuất file
+type FileExtractRequest struct {
+	ExtractRequest
+	// Download - Trả về file để tải xuống thay vì JSON (nội dung base64)
+	Download bool `json:"download"`
+}
+
+// FileResponse - File đã trích xuất trong response JSON
+type FileResponse struct {
+	Name     string `json:"name"`
+	MIMEType string `json:"mime_type"`
+	Size     int    `json:"size"`
+	Data     []byte `json:"data"`
+}
+
+// newFileResponse - Chuyển file đã trích xuất sang response, nil nếu không có file
+func newFileResponse(f *HiddenFile) *FileResponse {
+	if f == nil {
+		return nil
+	}
+	return &FileResponse{
+		Name:     f.Name,
+		MIMEType: f.MIME,
+		Size:     f.Size,
+		Data:     f.Data,
+	}
+}
+
+// FileEmbed - Giấu file tải lên (kèm tên, kích thước và kiểu MIME) vào văn bản phủ
+func (h *Handler) FileEmbed(c *gin.Context) {
+	var req FileEmbedRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+	data, ok := readUpload(c, req.File, MaxHiddenFileSize, ErrHiddenFileTooLarge.Error())
+	if !ok {
+		return
+	}
+
+	file, err := NewHiddenFile(req.File.Filename, data)
+	if err != nil {
+		utils.RespondWithValidationError(c, utils.FieldErrors{"file": err.Error()})
+		return
+	}
+	text, err := h.service.EmbedFile(req.Cover, file, req.options(req.Method))
+	respondEmbedded(c, text, err)
+}
+
+// readUpload - Đọc file tải lên tối đa limit byte. Trả về false nếu đã gửi response lỗi.
+func readUpload(c *gin.Context, header *multipart.FileHeader, limit int, tooLarge string) ([]byte, bool) {
+	if header.Size > int64(limit) {
+		utils.RespondWithValidationError(c, utils.FieldErrors{"file": tooLarge})
+		return nil, false
+	}
+	upload, err := header.Open()
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, "Failed to read uploaded file")
+		return nil, false
+	}
+	defer upload.Close()
+	data, err := io.ReadAll(io.LimitReader(upload, int64(limit)+1))
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, "Failed to read uploaded file")
+		return nil, false
+	}
+	if len(data) > limit {
+		utils.RespondWithValidationError(c, utils.FieldErrors{"file": tooLarge})
+		return nil, false
+	}
+	return data, true
+}
+
+// FileExtract - Trích xuất file hoặc message. File được trả về dạng tải xuống nếu
+// request yêu cầu, ngược lại trả về JSON như các endpoint trích xuất khác.
+func (h *Handler) FileExtract(c *gin.Context) {
+	var req FileExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	result, err := h.service.Extract(req.Text, req.options(req.Method))
+	if err != nil || result.File == nil || !req.Download {
+		respondExtracted(c, result, err)
+		return
+	}
+
+	c.Header("Content-Disposition", contentDisposition(result.File))
+	c.Data(http.StatusOK, result.File.MIME, result.File.Data)
+}
+
+// contentDisposition - Header tải xuống với tên file gốc. File không có tên được đặt
+// tên theo phần mở rộng của kiểu MIME.
+func contentDisposition(f *HiddenFile) string {
+	name := f.Name
+	if name == "" {
+		name = "hidden.bin"
+		if m := mimetype.Lookup(f.MIME); m != nil && m.Extension() != "" {
+			name = "hidden" + m.Extension()
+		}
+	}
+	header := mime.FormatMediaType("attachment", map[string]string{"filename": name})
+	if header == "" {
+		// Tên file chứa ký tự điều khiển không mã hóa được trong header
+		return "attachment"
+	}
+	return header
+}
@@ -0,0 +1,119 @@
+package stego
+
+// Số học trên trường hữu hạn GF(2^8) với đa thức sinh x^8 + x^4 + x^3 + x^2 + 1 (0x11d)
+// và phần tử sinh 2. Đa thức được biểu diễn với hệ số bậc cao nhất đứng đầu.
+
+const gfPrimitive = 0x11d
+
+var (
+	gfExp [512]byte
+	gfLog [256]int
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= gfPrimitive
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+func gfDiv(a, b byte) byte {
+	if b == 0 {
+		panic("gf256: division by zero")
+	}
+	if a == 0 {
+		return 0
+	}
+	return gfExp[(gfLog[a]+255-gfLog[b])%255]
+}
+
+// gfPow - Lũy thừa trong GF(256), power có thể âm
+func gfPow(x byte, power int) byte {
+	if x == 0 {
+		return 0
+	}
+	e := (gfLog[x] * power) % 255
+	if e < 0 {
+		e += 255
+	}
+	return gfExp[e]
+}
+
+func gfInverse(x byte) byte {
+	return gfExp[255-gfLog[x]]
+}
+
+func gfPolyScale(p []byte, x byte) []byte {
+	r := make([]byte, len(p))
+	for i := range p {
+		r[i] = gfMul(p[i], x)
+	}
+	return r
+}
+
+func gfPolyAdd(p, q []byte) []byte {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	r := make([]byte, n)
+	for i := range p {
+		r[i+n-len(p)] = p[i]
+	}
+	for i := range q {
+		r[i+n-len(q)] ^= q[i]
+	}
+	return r
+}
+
+func gfPolyMul(p, q []byte) []byte {
+	r := make([]byte, len(p)+len(q)-1)
+	for j := range q {
+		for i := range p {
+			r[i+j] ^= gfMul(p[i], q[j])
+		}
+	}
+	return r
+}
+
+// gfPolyEval - Tính giá trị đa thức tại x theo sơ đồ Horner
+func gfPolyEval(p []byte, x byte) byte {
+	y := p[0]
+	for i := 1; i < len(p); i++ {
+		y = gfMul(y, x) ^ p[i]
+	}
+	return y
+}
+
+// gfPolyDiv - Chia đa thức cho một đa thức monic, trả về thương và phần dư
+func gfPolyDiv(dividend, divisor []byte) ([]byte, []byte) {
+	out := append([]byte(nil), dividend...)
+	for i := 0; i < len(dividend)-(len(divisor)-1); i++ {
+		coef := out[i]
+		if coef == 0 {
+			continue
+		}
+		for j := 1; j < len(divisor); j++ {
+			if divisor[j] != 0 {
+				out[i+j] ^= gfMul(divisor[j], coef)
+			}
+		}
+	}
+	sep := len(out) - (len(divisor) - 1)
+	return out[:sep], out[sep:]
+}
@@ -0,0 +1,79 @@
+package stego
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/gosource"
+)
+
+// Tên và ID của phương pháp giấu tin trong mã nguồn Go
+const (
+	MethodGoSource   = "go-source"
+	methodIDGoSource = 15
+)
+
+// GoSource - Giấu dữ liệu trong mã nguồn Go bằng các cách viết tương đương do gói gosource
+// cung cấp. Mã nguồn kết quả đã qua gofmt và biên dịch ra cùng một chương trình.
+type GoSource struct {
+	encoder *gosource.Encoder
+}
+
+// NewGoSource - Tạo phương pháp mã nguồn Go với các kênh đã chọn
+func NewGoSource(channels gosource.Channels) *GoSource {
+	return &GoSource{encoder: gosource.New(channels)}
+}
+
+// ID - ID phương pháp trong header container
+func (g *GoSource) ID() uint8 {
+	return methodIDGoSource
+}
+
+// Name - Tên phương pháp
+func (g *GoSource) Name() string {
+	return MethodGoSource
+}
+
+// Capabilities - Đặc tính của phương pháp mã nguồn Go.
+// Mã nguồn được định dạng lại bằng gofmt trước khi đọc nên khoảng trắng không ảnh hưởng.
+func (g *GoSource) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: true,
+		Structured:            true,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong mã nguồn, 0 nếu không phải mã nguồn Go hợp lệ
+func (g *GoSource) Capacity(cover string) int {
+	bits, err := g.encoder.Capacity([]byte(cover))
+	if err != nil {
+		return 0
+	}
+	return bits
+}
+
+// Embed - Viết lại mã nguồn để mang data
+func (g *GoSource) Embed(cover string, data []byte) (string, error) {
+	if strings.TrimSpace(cover) == "" {
+		return "", ErrEmptyCover
+	}
+	out, err := g.encoder.Embed([]byte(cover), data)
+	if errors.Is(err, gosource.ErrCoverTooSmall) {
+		return "", ErrCoverTooSmall
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// Extract - Đọc dữ liệu từ các vị trí mang tin trong mã nguồn. Văn bản không phải mã nguồn
+// Go thì không mang dữ liệu, lỗi cú pháp không được báo cho người dùng khi tự nhận diện.
+func (g *GoSource) Extract(text string) ([]byte, error) {
+	data, err := g.encoder.Extract([]byte(text))
+	if errors.Is(err, gosource.ErrInvalidSource) {
+		return nil, ErrNoHiddenData
+	}
+	return data, err
+}
@@ -0,0 +1,68 @@
+package stego
+
+import (
+	"github.com/baolamabcd13/datahiding-text-app/internal/gosource"
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GoSourceFields - Các kênh giấu tin dùng cho request. Khi trích xuất phải dùng đúng các kênh
+// đã dùng khi giấu tin; để trống là bật tất cả.
+type GoSourceFields struct {
+	Channels []string `json:"channels" binding:"omitempty,dive,oneof=imports operands declarations comments"`
+}
+
+// method - Tạo phương pháp theo các kênh trong request
+func (f *GoSourceFields) method() (*GoSource, error) {
+	channels, err := gosource.ParseChannels(f.Channels)
+	if err != nil {
+		return nil, err
+	}
+	return NewGoSource(channels), nil
+}
+
+// GoSourceEmbedRequest - Request body cho giấu tin trong mã nguồn Go
+type GoSourceEmbedRequest struct {
+	EmbedFields
+	GoSourceFields
+}
+
+// GoSourceExtractRequest - Request body cho trích xuất tin từ mã nguồn Go
+type GoSourceExtractRequest struct {
+	ExtractFields
+	GoSourceFields
+}
+
+// GoSourceEmbed - Giấu message vào mã nguồn Go mà không thay đổi chương trình
+func (h *Handler) GoSourceEmbed(c *gin.Context) {
+	var req GoSourceEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodGoSource))
+	respondEmbedded(c, text, err)
+}
+
+// GoSourceExtract - Trích xuất message từ mã nguồn Go
+func (h *Handler) GoSourceExtract(c *gin.Context) {
+	var req GoSourceExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	result, err := h.service.ExtractWithMethod(method, req.Text, req.options(MethodGoSource))
+	respondExtracted(c, result, err)
+}
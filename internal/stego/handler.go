@@ -0,0 +1,342 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/gosource"
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler - Xử lý HTTP requests cho stego
+type Handler struct {
+	service Service
+}
+
+// NewHandler - Tạo handler mới
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// EmbedFields - Các trường chung của mọi request giấu tin
+type EmbedFields struct {
+	Cover      string      `json:"cover" binding:"required"`
+	Message    string      `json:"message" binding:"required"`
+	Passphrase string      `json:"passphrase"`
+	ECC        *ECCRequest `json:"ecc"`
+	// Scatter - Rải bit lên các vị trí mang tin theo khóa dẫn xuất từ passphrase
+	Scatter bool `json:"scatter"`
+	// StegoKey - Khóa rải bit riêng, độc lập với passphrase mã hóa
+	StegoKey string `json:"stego_key" binding:"required_if=Scatter true Passphrase ''"`
+}
+
+// options - Chuyển sang tùy chọn của service
+func (f *EmbedFields) options(method string) EmbedOptions {
+	return EmbedOptions{
+		Method:     method,
+		Passphrase: f.Passphrase,
+		ECC:        f.ECC.options(),
+		ScatterKey: scatterKey(f.Scatter, f.StegoKey, f.Passphrase),
+	}
+}
+
+// scatterKey - Khóa rải bit của request, rỗng nếu không rải bit.
+// Khi trích xuất phải gửi lại đúng khóa đã dùng.
+func scatterKey(scatter bool, stegoKey, passphrase string) string {
+	if stegoKey != "" {
+		return stegoKey
+	}
+	if scatter {
+		return passphrase
+	}
+	return ""
+}
+
+// EmbedRequest - Request body cho giấu tin
+type EmbedRequest struct {
+	Method string `json:"method"`
+	EmbedFields
+}
+
+// ECCRequest - Tùy chọn mã sửa lỗi trong request
+type ECCRequest struct {
+	ParityRatio float64 `json:"parity_ratio" binding:"omitempty,gt=0,lte=4"`
+	Interleave  int     `json:"interleave" binding:"omitempty,gte=1,lte=64"`
+}
+
+// options - Chuyển sang tùy chọn của service, nil nếu không bật ECC
+func (r *ECCRequest) options() *ECCOptions {
+	if r == nil {
+		return nil
+	}
+	return &ECCOptions{
+		ParityRatio: r.ParityRatio,
+		Interleave:  r.Interleave,
+	}
+}
+
+// EmbedResponse - Response cho giấu tin
+type EmbedResponse struct {
+	Text string `json:"text"`
+	// Warnings - Các cảnh báo về khả năng dữ liệu bị mất trên nền tảng đích
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ExtractFields - Các trường chung của mọi request trích xuất
+type ExtractFields struct {
+	Text       string `json:"text" binding:"required"`
+	Passphrase string `json:"passphrase"`
+	Scatter    bool   `json:"scatter"`
+	StegoKey   string `json:"stego_key" binding:"required_if=Scatter true Passphrase ''"`
+}
+
+// options - Chuyển sang tùy chọn của service
+func (f *ExtractFields) options(method string) ExtractOptions {
+	return ExtractOptions{
+		Method:     method,
+		Passphrase: f.Passphrase,
+		ScatterKey: scatterKey(f.Scatter, f.StegoKey, f.Passphrase),
+	}
+}
+
+// ExtractRequest - Request body cho trích xuất tin
+type ExtractRequest struct {
+	Method string `json:"method"`
+	ExtractFields
+}
+
+// ExtractResponse - Response cho trích xuất tin
+type ExtractResponse struct {
+	Method     string `json:"method"`
+	Version    uint8  `json:"version"`
+	Encrypted  bool   `json:"encrypted"`
+	Compressed bool   `json:"compressed"`
+	Corrected  int    `json:"corrected"`
+	Message    string `json:"message"`
+	// File - File đã giấu, chỉ có khi dữ liệu là file
+	File *FileResponse `json:"file,omitempty"`
+	// Split - Thông tin bộ mảnh, chỉ có khi message được ghép từ nhiều văn bản
+	Split *SplitInfo `json:"split,omitempty"`
+}
+
+// CapacityRequest - Request body cho ước lượng dung lượng
+type CapacityRequest struct {
+	Method      string      `json:"method"`
+	Cover       string      `json:"cover" binding:"required"`
+	Encrypt     bool        `json:"encrypt"`
+	ECC         *ECCRequest `json:"ecc"`
+	PayloadSize int         `json:"payload_size" binding:"gte=0"`
+	// Message - Message dự định giấu, thay cho payload_size để tính cả phần nén
+	Message string `json:"message"`
+}
+
+// ListMethods - Danh sách các phương pháp giấu tin và đặc tính của chúng
+func (h *Handler) ListMethods(c *gin.Context) {
+	utils.RespondWithSuccess(c, http.StatusOK, "Methods retrieved successfully", h.service.Methods())
+}
+
+// Embed - Giấu message vào văn bản phủ
+func (h *Handler) Embed(c *gin.Context) {
+	var req EmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	text, err := h.service.Embed(req.Cover, req.Message, req.options(req.Method))
+	respondEmbedded(c, text, err)
+}
+
+// Extract - Trích xuất message từ văn bản đã giấu tin
+func (h *Handler) Extract(c *gin.Context) {
+	var req ExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	result, err := h.service.Extract(req.Text, req.options(req.Method))
+	respondExtracted(c, result, err)
+}
+
+// Capacity - Ước lượng dung lượng của văn bản phủ và lượng văn bản cần cho payload
+func (h *Handler) Capacity(c *gin.Context) {
+	var req CapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	report, err := h.service.Capacity(req.Cover, CapacityOptions{
+		Method:      req.Method,
+		Encrypt:     req.Encrypt,
+		ECC:         req.ECC.options(),
+		PayloadSize: req.PayloadSize,
+		Message:     req.Message,
+	})
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+
+	if req.Message != "" && !report.Fits {
+		utils.RespondWithValidationError(c, utils.FieldErrors{
+			"message": fmt.Sprintf("message needs %d bits once packed but the cover holds %d, about %d words of cover are needed",
+				report.Required.Bits, report.CapacityBits, report.Required.Words),
+		})
+		return
+	}
+	if req.PayloadSize > 0 && !report.Fits {
+		utils.RespondWithValidationError(c, utils.FieldErrors{
+			"payload_size": fmt.Sprintf("payload_size must not exceed %d bytes for this cover, about %d words of cover are needed",
+				report.MaxPayloadBytes, report.Required.Words),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, "Capacity estimated successfully", report)
+}
+
+// respondEmbedded - Trả về văn bản đã giấu tin kèm cảnh báo, hoặc lỗi
+func respondEmbedded(c *gin.Context, text string, err error, warnings ...string) {
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, "Message embedded successfully", EmbedResponse{
+		Text:     text,
+		Warnings: warnings,
+	})
+}
+
+// respondExtracted - Trả về message đã trích xuất hoặc lỗi
+func respondExtracted(c *gin.Context, result *ExtractResult, err error) {
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, "Message extracted successfully", ExtractResponse{
+		Method:     result.Method,
+		Version:    result.Version,
+		Encrypted:  result.Encrypted,
+		Compressed: result.Compressed,
+		Corrected:  result.Corrected,
+		Message:    result.Message,
+		File:       newFileResponse(result.File),
+		Split:      result.Split,
+	})
+}
+
+// respondWithStegoError - Chuyển lỗi của service thành response phù hợp
+func respondWithStegoError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrWrongPassphrase):
+		utils.RespondWithError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, ErrNoHiddenData):
+		utils.RespondWithError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrTruncated), errors.Is(err, ErrCorrupted), errors.Is(err, ErrUnsupportedVersion),
+		errors.Is(err, ErrCoverTooSmall), errors.Is(err, ErrEmptyCover), errors.Is(err, ErrMixedToneStyles),
+		errors.Is(err, ErrAmbiguousSynonyms), errors.Is(err, ErrNoAcrosticCandidate),
+		errors.Is(err, ErrNgramModelTooPredictable), errors.Is(err, ErrCoverGenerationLimit),
+		errors.Is(err, ErrSecretShare), errors.Is(err, ErrNotAShare), errors.Is(err, ErrNotEnoughShares),
+		errors.Is(err, ErrMixedShareSets), errors.Is(err, ErrMarkdownRenderingChanged),
+		errors.Is(err, gosource.ErrRewriteFailed), errors.Is(err, ErrDocumentValueChanged):
+		utils.RespondWithError(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+	}
+}
+
+// SetupRoutes - Thiết lập routes cho stego
+func (h *Handler) SetupRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	stego := router.Group("/stego")
+	{
+		// Routes cần xác thực
+		stego.Use(authMiddleware)
+		stego.GET("/methods", h.ListMethods)
+		stego.POST("/embed", h.Embed)
+		stego.POST("/extract", h.Extract)
+		stego.POST("/capacity", h.Capacity)
+		stego.GET("/platforms", h.ListPlatforms)
+
+		whitespace := stego.Group("/whitespace")
+		whitespace.POST("/embed", h.WhitespaceEmbed)
+		whitespace.POST("/extract", h.WhitespaceExtract)
+
+		homoglyph := stego.Group("/homoglyph")
+		homoglyph.GET("/tables", h.HomoglyphTables)
+		homoglyph.POST("/embed", h.HomoglyphEmbed)
+		homoglyph.POST("/extract", h.HomoglyphExtract)
+
+		variation := stego.Group("/variation-selector")
+		variation.POST("/embed", h.VariationEmbed)
+		variation.POST("/extract", h.VariationExtract)
+
+		toneMark := stego.Group("/tone-mark")
+		toneMark.GET("/rules", h.ToneMarkRules)
+		toneMark.POST("/embed", h.ToneMarkEmbed)
+		toneMark.POST("/extract", h.ToneMarkExtract)
+
+		normalization := stego.Group("/normalization")
+		normalization.POST("/analyze", h.NormalizationAnalyze)
+		normalization.POST("/embed", h.NormalizationEmbed)
+		normalization.POST("/extract", h.NormalizationExtract)
+
+		synonym := stego.Group("/synonym")
+		synonym.GET("/languages", h.SynonymLanguages)
+		synonym.POST("/embed", h.SynonymEmbed)
+		synonym.POST("/extract", h.SynonymExtract)
+
+		spacing := stego.Group("/spacing")
+		spacing.POST("/embed", h.SpacingEmbed)
+		spacing.POST("/extract", h.SpacingExtract)
+
+		acrostic := stego.Group("/acrostic")
+		acrostic.POST("/compose", h.AcrosticCompose)
+		acrostic.POST("/extract", h.AcrosticExtract)
+
+		ngram := stego.Group("/ngram")
+		ngram.GET("/models", h.NgramModels)
+		ngram.POST("/generate", h.NgramGenerate)
+		ngram.POST("/extract", h.NgramExtract)
+
+		cover := stego.Group("/cover")
+		cover.POST("/generate", h.GenerateCover)
+
+		file := stego.Group("/file")
+		file.POST("/embed", h.FileEmbed)
+		file.POST("/extract", h.FileExtract)
+
+		share := stego.Group("/share")
+		share.POST("/split", h.ShareSplit)
+		share.POST("/combine", h.ShareCombine)
+
+		docx := stego.Group("/docx")
+		docx.POST("/embed", h.DocxEmbed)
+		docx.POST("/extract", h.DocxExtract)
+
+		html := stego.Group("/html")
+		html.POST("/embed", h.HTMLEmbed)
+		html.POST("/extract", h.HTMLExtract)
+
+		markdown := stego.Group("/markdown")
+		markdown.POST("/embed", h.MarkdownEmbed)
+		markdown.POST("/extract", h.MarkdownExtract)
+
+		goSource := stego.Group("/go-source")
+		goSource.POST("/embed", h.GoSourceEmbed)
+		goSource.POST("/extract", h.GoSourceExtract)
+
+		json := stego.Group("/json")
+		json.POST("/embed", h.JSONEmbed)
+		json.POST("/extract", h.JSONExtract)
+
+		yaml := stego.Group("/yaml")
+		yaml.POST("/embed", h.YAMLEmbed)
+		yaml.POST("/extract", h.YAMLExtract)
+	}
+}
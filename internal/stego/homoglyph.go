@@ -0,0 +1,328 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tên và ID của phương pháp ký tự đồng dạng
+const (
+	MethodHomoglyph   = "homoglyph"
+	methodIDHomoglyph = 3
+)
+
+// DefaultHomoglyphTable - Phiên bản bảng ký tự đồng dạng mặc định
+const DefaultHomoglyphTable = "v1"
+
+// ErrUnknownHomoglyphTable - Phiên bản bảng ký tự đồng dạng không tồn tại
+var ErrUnknownHomoglyphTable = errors.New("unknown homoglyph table")
+
+// homoglyphPair - Một chữ Latin và ký tự đồng dạng thay thế nó.
+// alternates là các ký tự đồng dạng khác chỉ được chấp nhận khi trích xuất.
+type homoglyphPair struct {
+	latin      rune
+	confusable rune
+	alternates []rune
+}
+
+// homoglyphTables - Các bảng ký tự đồng dạng theo phiên bản.
+// Không sửa một phiên bản đã phát hành vì văn bản cũ phải trích xuất được bằng đúng bảng đó;
+// thay đổi bảng thì thêm phiên bản mới.
+var homoglyphTables = map[string][]homoglyphPair{
+	// v1 - Chữ Cyrillic và Greek trông giống chữ Latin trong đa số font sans-serif
+	"v1": homoglyphPairsV1,
+	// v1-strict - Chỉ giữ các cặp giống nhau cả trong font serif và monospace
+	"v1-strict": filterHomoglyphPairs(homoglyphPairsV1, "acepoxyABCEHKMOPTX"),
+}
+
+var homoglyphPairsV1 = []homoglyphPair{
+	{latin: 'a', confusable: '\u0430'},
+	{latin: 'c', confusable: '\u0441'},
+	{latin: 'e', confusable: '\u0435'},
+	{latin: 'i', confusable: '\u0456'},
+	{latin: 'j', confusable: '\u0458'},
+	{latin: 'o', confusable: '\u043E', alternates: []rune{'\u03BF'}},
+	{latin: 'p', confusable: '\u0440', alternates: []rune{'\u03C1'}},
+	{latin: 's', confusable: '\u0455'},
+	{latin: 'x', confusable: '\u0445'},
+	{latin: 'y', confusable: '\u0443'},
+	{latin: 'A', confusable: '\u0410', alternates: []rune{'\u0391'}},
+	{latin: 'B', confusable: '\u0412', alternates: []rune{'\u0392'}},
+	{latin: 'C', confusable: '\u0421'},
+	{latin: 'E', confusable: '\u0415', alternates: []rune{'\u0395'}},
+	{latin: 'H', confusable: '\u041D', alternates: []rune{'\u0397'}},
+	{latin: 'I', confusable: '\u0406', alternates: []rune{'\u0399'}},
+	{latin: 'J', confusable: '\u0408'},
+	{latin: 'K', confusable: '\u041A', alternates: []rune{'\u039A'}},
+	{latin: 'M', confusable: '\u041C', alternates: []rune{'\u039C'}},
+	{latin: 'N', confusable: '\u039D'},
+	{latin: 'O', confusable: '\u041E', alternates: []rune{'\u039F'}},
+	{latin: 'P', confusable: '\u0420', alternates: []rune{'\u03A1'}},
+	{latin: 'S', confusable: '\u0405'},
+	{latin: 'T', confusable: '\u0422', alternates: []rune{'\u03A4'}},
+	{latin: 'X', confusable: '\u0425', alternates: []rune{'\u03A7'}},
+	{latin: 'Y', confusable: '\u04AE', alternates: []rune{'\u03A5'}},
+	{latin: 'Z', confusable: '\u0396'},
+}
+
+// filterHomoglyphPairs - Lấy các cặp có chữ Latin nằm trong letters
+func filterHomoglyphPairs(pairs []homoglyphPair, letters string) []homoglyphPair {
+	var filtered []homoglyphPair
+	for _, pair := range pairs {
+		if strings.ContainsRune(letters, pair.latin) {
+			filtered = append(filtered, pair)
+		}
+	}
+	return filtered
+}
+
+// HomoglyphTable - Bảng ký tự đồng dạng đã áp dụng danh sách loại trừ
+type HomoglyphTable struct {
+	Version string
+	// Exclude - Các ký tự không dùng làm vật mang, kể cả chữ có dấu như "ố"
+	Exclude string
+
+	pairs       []homoglyphPair
+	confusables map[rune]rune // chữ Latin -> ký tự đồng dạng
+	latins      map[rune]rune // ký tự đồng dạng (kể cả alternates) -> chữ Latin
+	excluded    map[rune]bool
+}
+
+// NewHomoglyphTable - Tạo bảng theo phiên bản, bỏ các ký tự trong exclude.
+// Một cặp bị loại nếu chữ Latin hoặc ký tự đồng dạng của nó nằm trong exclude.
+func NewHomoglyphTable(version, exclude string) (*HomoglyphTable, error) {
+	if version == "" {
+		version = DefaultHomoglyphTable
+	}
+	pairs, ok := homoglyphTables[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHomoglyphTable, version)
+	}
+
+	t := &HomoglyphTable{
+		Version:     version,
+		Exclude:     exclude,
+		confusables: make(map[rune]rune),
+		latins:      make(map[rune]rune),
+		excluded:    make(map[rune]bool),
+	}
+	for _, r := range norm.NFC.String(exclude) {
+		t.excluded[r] = true
+	}
+	for _, pair := range pairs {
+		if t.excluded[pair.latin] || t.excluded[pair.confusable] {
+			continue
+		}
+		t.pairs = append(t.pairs, pair)
+		t.confusables[pair.latin] = pair.confusable
+		t.latins[pair.confusable] = pair.latin
+		for _, alt := range pair.alternates {
+			if !t.excluded[alt] {
+				t.latins[alt] = pair.latin
+			}
+		}
+	}
+	if len(t.pairs) == 0 {
+		return nil, errors.New("homoglyph table has no usable pairs after exclusions")
+	}
+	return t, nil
+}
+
+// HomoglyphPairInfo - Thông tin một cặp ký tự đồng dạng
+type HomoglyphPairInfo struct {
+	Latin      string   `json:"latin"`
+	Confusable string   `json:"confusable"`
+	Codepoint  string   `json:"codepoint"`
+	Alternates []string `json:"alternates,omitempty"`
+}
+
+// HomoglyphTableInfo - Thông tin một phiên bản bảng ký tự đồng dạng
+type HomoglyphTableInfo struct {
+	Version string              `json:"version"`
+	Pairs   []HomoglyphPairInfo `json:"pairs"`
+}
+
+// HomoglyphTables - Danh sách các phiên bản bảng ký tự đồng dạng
+func HomoglyphTables() []HomoglyphTableInfo {
+	versions := make([]string, 0, len(homoglyphTables))
+	for version := range homoglyphTables {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	infos := make([]HomoglyphTableInfo, 0, len(versions))
+	for _, version := range versions {
+		info := HomoglyphTableInfo{Version: version}
+		for _, pair := range homoglyphTables[version] {
+			pairInfo := HomoglyphPairInfo{
+				Latin:      string(pair.latin),
+				Confusable: string(pair.confusable),
+				Codepoint:  fmt.Sprintf("U+%04X", pair.confusable),
+			}
+			for _, alt := range pair.alternates {
+				pairInfo.Alternates = append(pairInfo.Alternates, fmt.Sprintf("U+%04X", alt))
+			}
+			info.Pairs = append(info.Pairs, pairInfo)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Homoglyph - Giấu dữ liệu bằng cách thay chữ Latin (kể cả chữ tiếng Việt có dấu)
+// bằng ký tự Cyrillic/Greek trông giống hệt. Mỗi chữ có trong bảng mang 1 bit:
+// chữ Latin là 0, ký tự đồng dạng là 1. Dấu thanh và dấu phụ được giữ nguyên.
+type Homoglyph struct {
+	Table *HomoglyphTable
+}
+
+// NewHomoglyph - Tạo phương pháp ký tự đồng dạng với bảng và danh sách loại trừ
+func NewHomoglyph(version, exclude string) (*Homoglyph, error) {
+	table, err := NewHomoglyphTable(version, exclude)
+	if err != nil {
+		return nil, err
+	}
+	return &Homoglyph{Table: table}, nil
+}
+
+// ID - ID phương pháp trong header container
+func (h *Homoglyph) ID() uint8 {
+	return methodIDHomoglyph
+}
+
+// Name - Tên phương pháp
+func (h *Homoglyph) Name() string {
+	return MethodHomoglyph
+}
+
+// Capabilities - Đặc tính của phương pháp ký tự đồng dạng
+func (h *Homoglyph) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: true,
+		BitsPerChar:           1,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong văn bản phủ
+func (h *Homoglyph) Capacity(cover string) int {
+	return len(h.slots(cover))
+}
+
+// Embed - Thay các chữ trong cover theo từng bit của data
+func (h *Homoglyph) Embed(cover string, data []byte) (string, error) {
+	if cover == "" {
+		return "", ErrEmptyCover
+	}
+	slots := h.slots(cover)
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > len(slots) {
+		return "", ErrCoverTooSmall
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(cover) + len(bits))
+	pos := 0
+	for i, bit := range bits {
+		slot := slots[i]
+		sb.WriteString(cover[pos:slot.start])
+		if slot.value == bit {
+			sb.WriteString(cover[slot.start:slot.end])
+		} else {
+			base := slot.latin
+			if bit == 1 {
+				base = h.Table.confusables[slot.latin]
+			}
+			sb.WriteString(norm.NFC.String(string(base) + slot.marks))
+		}
+		pos = slot.end
+	}
+	sb.WriteString(cover[pos:])
+	return sb.String(), nil
+}
+
+// Extract - Đọc bit từ mọi chữ có trong bảng của văn bản
+func (h *Homoglyph) Extract(text string) ([]byte, error) {
+	slots := h.slots(text)
+	bits := make([]byte, len(slots))
+	for i, slot := range slots {
+		bits[i] = slot.value
+	}
+	return bitutil.BitsToBytes(bits), nil
+}
+
+// glyphSlot - Một chữ mang tin: vị trí byte trong văn bản, chữ Latin tương ứng,
+// các dấu kết hợp (dạng NFD) và bit hiện tại
+type glyphSlot struct {
+	start, end int
+	latin      rune
+	marks      string
+	value      byte
+}
+
+// slots - Tìm các chữ mang tin. Chỉ các từ gồm toàn chữ Latin hoặc ký tự trong bảng
+// mới được dùng, nên các từ tiếng Nga/Hy Lạp thật hay chữ ngoài bảng trong văn bản
+// không bị đọc nhầm và số vật mang không đổi sau khi giấu tin.
+func (h *Homoglyph) slots(text string) []glyphSlot {
+	var slots, word []glyphSlot
+	carrier := true
+	flush := func() {
+		if carrier {
+			slots = append(slots, word...)
+		}
+		word = word[:0]
+		carrier = true
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		// Gộp chữ với các dấu kết hợp theo sau
+		end := i + size
+		for end < len(text) {
+			mark, n := utf8.DecodeRuneInString(text[end:])
+			if !unicode.Is(unicode.Mn, mark) {
+				break
+			}
+			end += n
+		}
+		if !unicode.IsLetter(r) {
+			flush()
+			i = end
+			continue
+		}
+
+		segment := norm.NFD.String(text[i:end])
+		base, n := utf8.DecodeRuneInString(segment)
+		marks := segment[n:]
+		if latin, ok := h.Table.latins[base]; ok {
+			if h.Table.isExcluded(latin, marks) {
+				carrier = false
+			} else {
+				word = append(word, glyphSlot{start: i, end: end, latin: latin, marks: marks, value: 1})
+			}
+		} else if _, ok := h.Table.confusables[base]; ok && !h.Table.isExcluded(base, marks) {
+			word = append(word, glyphSlot{start: i, end: end, latin: base, marks: marks, value: 0})
+		} else if !unicode.Is(unicode.Latin, base) {
+			carrier = false
+		}
+		i = end
+	}
+	flush()
+	return slots
+}
+
+// isExcluded - Kiểm tra chữ có dấu (ví dụ "ố") có bị loại trừ không
+func (t *HomoglyphTable) isExcluded(latin rune, marks string) bool {
+	if marks == "" {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(norm.NFC.String(string(latin) + marks))
+	return t.excluded[r]
+}
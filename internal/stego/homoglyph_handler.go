@@ -0,0 +1,85 @@
+package stego
+
+import (
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// HomoglyphFields - Bảng ký tự đồng dạng cho request.
+// Để trống cả hai trường để dùng bảng cấu hình trên server; khi trích xuất phải
+// dùng đúng bảng và danh sách loại trừ đã dùng khi giấu tin.
+type HomoglyphFields struct {
+	Table   string `json:"table"`
+	Exclude string `json:"exclude"`
+}
+
+// method - Tạo phương pháp theo bảng trong request, nil nếu dùng cấu hình server
+func (f *HomoglyphFields) method() (*Homoglyph, error) {
+	if f.Table == "" && f.Exclude == "" {
+		return nil, nil
+	}
+	return NewHomoglyph(f.Table, f.Exclude)
+}
+
+// HomoglyphEmbedRequest - Request body cho giấu tin bằng ký tự đồng dạng
+type HomoglyphEmbedRequest struct {
+	EmbedFields
+	HomoglyphFields
+}
+
+// HomoglyphExtractRequest - Request body cho trích xuất tin từ ký tự đồng dạng
+type HomoglyphExtractRequest struct {
+	ExtractFields
+	HomoglyphFields
+}
+
+// HomoglyphTables - Danh sách các phiên bản bảng ký tự đồng dạng
+func (h *Handler) HomoglyphTables(c *gin.Context) {
+	utils.RespondWithSuccess(c, http.StatusOK, "Homoglyph tables retrieved successfully", HomoglyphTables())
+}
+
+// HomoglyphEmbed - Giấu message bằng cách thay chữ Latin bằng ký tự đồng dạng
+func (h *Handler) HomoglyphEmbed(c *gin.Context) {
+	var req HomoglyphEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	if method == nil {
+		text, err := h.service.Embed(req.Cover, req.Message, req.options(MethodHomoglyph))
+		respondEmbedded(c, text, err)
+		return
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodHomoglyph))
+	respondEmbedded(c, text, err)
+}
+
+// HomoglyphExtract - Trích xuất message từ các ký tự đồng dạng
+func (h *Handler) HomoglyphExtract(c *gin.Context) {
+	var req HomoglyphExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	if method == nil {
+		result, err := h.service.Extract(req.Text, req.options(MethodHomoglyph))
+		respondExtracted(c, result, err)
+		return
+	}
+	result, err := h.service.ExtractWithMethod(method, req.Text, req.options(MethodHomoglyph))
+	respondExtracted(c, result, err)
+}
@@ -0,0 +1,686 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+	"golang.org/x/net/html"
+)
+
+// Tên và ID của phương pháp giấu tin trong mã HTML
+const (
+	MethodHTML   = "html"
+	methodIDHTML = 13
+)
+
+// Tên các lớp vật mang của phương pháp HTML
+const (
+	HTMLAttributeOrder = "attribute-order"
+	HTMLQuotes         = "quotes"
+	HTMLCase           = "case"
+	HTMLWhitespace     = "whitespace"
+	HTMLEntities       = "entities"
+)
+
+// ErrInvalidHTML - Không phân tích được văn bản phủ thành các token HTML
+var ErrInvalidHTML = errors.New("cover is not a valid HTML document")
+
+// HTMLClasses - Các lớp vật mang được bật. Mọi lớp đều chỉ thay đổi cách viết mã nguồn,
+// trình duyệt dựng ra cùng một cây DOM nên trang hiển thị giống hệt.
+type HTMLClasses struct {
+	// AttributeOrder - Thứ tự các thuộc tính trong thẻ mở, floor(log2(n!)) bit cho n thuộc tính
+	AttributeOrder bool
+	// Quotes - Giá trị thuộc tính trong dấu nháy kép (0) hoặc nháy đơn (1)
+	Quotes bool
+	// Case - Tên thẻ viết thường (0) hoặc viết hoa (1)
+	Case bool
+	// Whitespace - Số dấu cách cuối đoạn khoảng trắng giữa hai thẻ chẵn (0) hoặc lẻ (1)
+	Whitespace bool
+	// Entities - Ký tự viết trực tiếp (0) hoặc dạng thực thể (1) trong nội dung văn bản
+	Entities bool
+}
+
+// AllHTMLClasses - Bật tất cả các lớp vật mang
+var AllHTMLClasses = HTMLClasses{AttributeOrder: true, Quotes: true, Case: true, Whitespace: true, Entities: true}
+
+// ParseHTMLClasses - Chuyển danh sách tên lớp thành HTMLClasses, rỗng là bật tất cả
+func ParseHTMLClasses(names []string) (HTMLClasses, error) {
+	if len(names) == 0 {
+		return AllHTMLClasses, nil
+	}
+	var classes HTMLClasses
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case HTMLAttributeOrder:
+			classes.AttributeOrder = true
+		case HTMLQuotes:
+			classes.Quotes = true
+		case HTMLCase:
+			classes.Case = true
+		case HTMLWhitespace:
+			classes.Whitespace = true
+		case HTMLEntities:
+			classes.Entities = true
+		default:
+			return classes, fmt.Errorf("unknown html class %q", name)
+		}
+	}
+	return classes, nil
+}
+
+// htmlEntityChars - Các ký tự có thể viết trực tiếp hoặc bằng thực thể trong nội dung văn bản.
+// Chỉ đúng dạng thực thể trong bảng được coi là vật mang, các cách viết khác được giữ nguyên.
+var htmlEntityChars = []struct {
+	literal string
+	entity  string
+}{
+	{`"`, "&quot;"},
+	{"'", "&#39;"},
+	{">", "&gt;"},
+	{"\u00a0", "&nbsp;"},
+}
+
+// htmlRawTextElements - Các phần tử mà nội dung được tokenizer đọc nguyên văn (script, style...),
+// nội dung này không bao giờ bị sửa
+var htmlRawTextElements = map[string]bool{
+	"iframe": true, "noembed": true, "noframes": true, "noscript": true, "plaintext": true,
+	"script": true, "style": true, "textarea": true, "title": true, "xmp": true,
+}
+
+// htmlPreformattedElements - Các phần tử giữ nguyên khoảng trắng khi hiển thị
+var htmlPreformattedElements = map[string]bool{
+	"pre": true, "listing": true, "textarea": true, "plaintext": true,
+}
+
+// HTML - Giấu dữ liệu trong mã nguồn HTML mà không thay đổi trang hiển thị: thứ tự thuộc tính,
+// kiểu dấu nháy, chữ hoa/thường của tên thẻ, khoảng trắng giữa các thẻ và cách viết thực thể.
+// Mọi phần khác của tài liệu được giữ nguyên từng byte.
+type HTML struct {
+	Classes HTMLClasses
+}
+
+// NewHTML - Tạo phương pháp HTML với các lớp vật mang đã chọn
+func NewHTML(classes HTMLClasses) *HTML {
+	return &HTML{Classes: classes}
+}
+
+// ID - ID phương pháp trong header container
+func (h *HTML) ID() uint8 {
+	return methodIDHTML
+}
+
+// Name - Tên phương pháp
+func (h *HTML) Name() string {
+	return MethodHTML
+}
+
+// Capabilities - Đặc tính của phương pháp HTML.
+// Khoảng trắng ở đầu và cuối tài liệu không mang tin; NFKC đổi U+00A0 thành dấu cách thường.
+func (h *HTML) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: false,
+		Structured:            true,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong tài liệu, 0 nếu không phân tích được
+func (h *HTML) Capacity(cover string) int {
+	tokens, err := parseHTML(cover)
+	if err != nil {
+		return 0
+	}
+	bits := 0
+	for i := range tokens {
+		bits += h.tokenBits(&tokens[i])
+	}
+	return bits
+}
+
+// Embed - Viết lại các vật mang theo từng nhóm bit của data, các token sau phần dữ liệu
+// được giữ nguyên
+func (h *HTML) Embed(cover string, data []byte) (string, error) {
+	if cover == "" {
+		return "", ErrEmptyCover
+	}
+	tokens, err := parseHTML(cover)
+	if err != nil {
+		return "", err
+	}
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > h.Capacity(cover) {
+		return "", ErrCoverTooSmall
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(cover) + len(bits))
+	for i := range tokens {
+		t := &tokens[i]
+		n := h.tokenBits(t)
+		if n == 0 || len(bits) == 0 {
+			sb.WriteString(t.raw)
+			continue
+		}
+		// Token cuối có thể mang nhiều bit hơn phần còn lại, phần thiếu được đệm bằng 0
+		chunk := make([]byte, n)
+		bits = bits[copy(chunk, bits):]
+		sb.WriteString(h.writeToken(t, chunk))
+	}
+	return sb.String(), nil
+}
+
+// Extract - Đọc bit từ các vật mang theo thứ tự trong tài liệu
+func (h *HTML) Extract(text string) ([]byte, error) {
+	tokens, err := parseHTML(text)
+	if err != nil {
+		return nil, err
+	}
+	var bits []byte
+	for i := range tokens {
+		bits = h.readToken(&tokens[i], bits)
+	}
+	return bitutil.BitsToBytes(bits), nil
+}
+
+// Các loại token HTML
+const (
+	// htmlTokenOther - Token được giữ nguyên (comment, doctype, nội dung script...)
+	htmlTokenOther = iota
+	htmlTokenTag
+	// htmlTokenSpace - Đoạn chỉ gồm khoảng trắng giữa hai thẻ
+	htmlTokenSpace
+	// htmlTokenText - Nội dung văn bản
+	htmlTokenText
+)
+
+// htmlToken - Một token cùng mã nguồn gốc của nó
+type htmlToken struct {
+	raw  string
+	kind int
+	tag  *htmlTag
+}
+
+// parseHTML - Tách tài liệu thành các token. Ghép mã nguồn các token lại phải được đúng
+// tài liệu ban đầu, nhờ vậy phần không mang tin được giữ nguyên từng byte.
+func parseHTML(doc string) ([]htmlToken, error) {
+	z := html.NewTokenizer(strings.NewReader(doc))
+	var tokens []htmlToken
+	size := 0
+	rawText := false
+	preformatted := 0
+	for {
+		tt := z.Next()
+		t := htmlToken{raw: string(z.Raw())}
+		size += len(t.raw)
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidHTML, z.Err())
+			}
+			if t.raw != "" {
+				tokens = append(tokens, t)
+			}
+			break
+		}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			if tag, ok := parseHTMLTag(t.raw); ok {
+				t.kind = htmlTokenTag
+				t.tag = tag
+			}
+			name, _ := z.TagName()
+			switch {
+			case tt == html.EndTagToken:
+				if htmlPreformattedElements[string(name)] && preformatted > 0 {
+					preformatted--
+				}
+			case htmlPreformattedElements[string(name)]:
+				preformatted++
+			}
+			tokens = append(tokens, t)
+			rawText = tt != html.EndTagToken && htmlRawTextElements[string(name)]
+			continue
+		case html.TextToken:
+			switch {
+			case rawText:
+			case strings.Trim(t.raw, htmlSpaceChars) == "":
+				if preformatted == 0 {
+					t.kind = htmlTokenSpace
+				}
+			default:
+				t.kind = htmlTokenText
+			}
+		}
+		tokens = append(tokens, t)
+		rawText = false
+	}
+	if size != len(doc) {
+		return nil, fmt.Errorf("%w: tokenizer did not preserve the document", ErrInvalidHTML)
+	}
+
+	// Khoảng trắng đầu và cuối tài liệu thường bị cắt khi phục vụ lại nên không mang tin
+	if len(tokens) > 0 && tokens[0].kind == htmlTokenSpace {
+		tokens[0].kind = htmlTokenOther
+	}
+	if len(tokens) > 0 && tokens[len(tokens)-1].kind == htmlTokenSpace {
+		tokens[len(tokens)-1].kind = htmlTokenOther
+	}
+	return tokens, nil
+}
+
+// tokenBits - Số bit token mang được với các lớp đã bật
+func (h *HTML) tokenBits(t *htmlToken) int {
+	switch t.kind {
+	case htmlTokenTag:
+		bits := 0
+		if h.Classes.Case && t.tag.caseSlot() {
+			bits++
+		}
+		if h.Classes.AttributeOrder {
+			bits += t.tag.orderBits()
+		}
+		if h.Classes.Quotes {
+			bits += len(t.tag.quoteSlots())
+		}
+		return bits
+	case htmlTokenSpace:
+		if h.Classes.Whitespace {
+			return 1
+		}
+	case htmlTokenText:
+		if h.Classes.Entities {
+			return len(htmlEntitySlots(t.raw))
+		}
+	}
+	return 0
+}
+
+// readToken - Đọc các bit của token và nối vào bits
+func (h *HTML) readToken(t *htmlToken, bits []byte) []byte {
+	switch t.kind {
+	case htmlTokenTag:
+		tag := t.tag
+		if h.Classes.Case && tag.caseSlot() {
+			bits = append(bits, tag.caseValue())
+		}
+		if n := tag.orderBits(); h.Classes.AttributeOrder && n > 0 {
+			// Chỉ số hoán vị được ghi trong n bit thấp
+			bits = bitutil.AppendUintBits(bits, bitutil.PermutationIndex(tag.order()), n)
+		}
+		if h.Classes.Quotes {
+			for _, i := range tag.quoteSlots() {
+				bits = append(bits, tag.quoteValue(i))
+			}
+		}
+	case htmlTokenSpace:
+		if h.Classes.Whitespace {
+			bits = append(bits, htmlSpaceValue(t.raw))
+		}
+	case htmlTokenText:
+		if h.Classes.Entities {
+			for _, slot := range htmlEntitySlots(t.raw) {
+				bits = append(bits, slot.value)
+			}
+		}
+	}
+	return bits
+}
+
+// writeToken - Viết lại token mang đúng len(bits) == tokenBits(t) bit
+func (h *HTML) writeToken(t *htmlToken, bits []byte) string {
+	switch t.kind {
+	case htmlTokenTag:
+		tag := t.tag
+		caseValue := tag.caseValue()
+		if h.Classes.Case && tag.caseSlot() {
+			caseValue, bits = bits[0], bits[1:]
+		}
+		order := tag.order()
+		if n := tag.orderBits(); h.Classes.AttributeOrder && n > 0 {
+			order = bitutil.PermutationFromIndex(len(order), bitutil.UintFromBits(bits[:n]))
+			bits = bits[n:]
+		}
+		quotes := make(map[int]byte)
+		if h.Classes.Quotes {
+			for j, i := range tag.quoteSlots() {
+				quotes[i] = bits[j]
+			}
+		}
+		return tag.render(caseValue, order, quotes)
+	case htmlTokenSpace:
+		return setHTMLSpaceValue(t.raw, bits[0])
+	case htmlTokenText:
+		var sb strings.Builder
+		prev := 0
+		for j, slot := range htmlEntitySlots(t.raw) {
+			sb.WriteString(t.raw[prev:slot.start])
+			if bits[j] == 1 {
+				sb.WriteString(htmlEntityChars[slot.char].entity)
+			} else {
+				sb.WriteString(htmlEntityChars[slot.char].literal)
+			}
+			prev = slot.end
+		}
+		sb.WriteString(t.raw[prev:])
+		return sb.String()
+	}
+	return t.raw
+}
+
+// htmlSpaceChars - Các ký tự khoảng trắng theo đặc tả HTML
+const htmlSpaceChars = " \t\n\r\f"
+
+func isHTMLSpace(c byte) bool {
+	return strings.IndexByte(htmlSpaceChars, c) >= 0
+}
+
+// htmlSpaceValue - Bit của đoạn khoảng trắng: tính chẵn lẻ của số dấu cách ở cuối.
+// Trình duyệt gộp cả đoạn thành một dấu cách nên số dấu cách không ảnh hưởng hiển thị.
+func htmlSpaceValue(raw string) byte {
+	return byte((len(raw) - len(strings.TrimRight(raw, " "))) % 2)
+}
+
+// setHTMLSpaceValue - Thêm hoặc bớt một dấu cách cuối để đổi bit, đoạn khoảng trắng
+// không bao giờ bị xóa hết
+func setHTMLSpaceValue(raw string, value byte) string {
+	if htmlSpaceValue(raw) == value {
+		return raw
+	}
+	if len(raw) > 1 && strings.HasSuffix(raw, " ") {
+		return raw[:len(raw)-1]
+	}
+	return raw + " "
+}
+
+// htmlEntitySlot - Một ký tự trong htmlEntityChars: vị trí byte, chỉ số trong bảng và
+// giá trị (1 nếu viết dạng thực thể)
+type htmlEntitySlot struct {
+	start, end int
+	char       int
+	value      byte
+}
+
+// htmlEntitySlots - Tìm các ký tự mang tin trong nội dung văn bản theo thứ tự
+func htmlEntitySlots(text string) []htmlEntitySlot {
+	var slots []htmlEntitySlot
+	for i := 0; i < len(text); {
+		found := false
+		for c, e := range htmlEntityChars {
+			switch {
+			case strings.HasPrefix(text[i:], e.entity):
+				slots = append(slots, htmlEntitySlot{start: i, end: i + len(e.entity), char: c, value: 1})
+			case strings.HasPrefix(text[i:], e.literal):
+				slots = append(slots, htmlEntitySlot{start: i, end: i + len(e.literal), char: c, value: 0})
+			default:
+				continue
+			}
+			i = slots[len(slots)-1].end
+			found = true
+			break
+		}
+		if !found {
+			_, n := utf8.DecodeRuneInString(text[i:])
+			i += n
+		}
+	}
+	return slots
+}
+
+// htmlTag - Thẻ HTML đã tách tên và các thuộc tính trên mã nguồn gốc
+type htmlTag struct {
+	raw                string
+	end                bool
+	nameStart, nameEnd int
+	attrs              []htmlAttr
+}
+
+// htmlAttr - Một thuộc tính trong thẻ: khoảng byte từ đầu tên tới hết giá trị,
+// tên viết thường và vị trí dấu nháy mở (-1 nếu giá trị không có dấu nháy)
+type htmlAttr struct {
+	start, end int
+	name       string
+	quote      int
+}
+
+// parseHTMLTag - Tách thẻ mở hoặc thẻ đóng theo đúng cách tokenizer của x/net/html đọc
+// thuộc tính, để vị trí của từng thuộc tính khớp với cách trình duyệt hiểu thẻ
+func parseHTMLTag(raw string) (*htmlTag, bool) {
+	if len(raw) < 3 || raw[0] != '<' || raw[len(raw)-1] != '>' {
+		return nil, false
+	}
+	t := &htmlTag{raw: raw, nameStart: 1}
+	if raw[1] == '/' {
+		t.end = true
+		t.nameStart = 2
+	}
+	// n - Vị trí dấu '>' kết thúc thẻ
+	n := len(raw) - 1
+	i := t.nameStart
+	for i < n && !isHTMLSpace(raw[i]) && raw[i] != '/' {
+		i++
+	}
+	t.nameEnd = i
+	if t.nameEnd == t.nameStart {
+		return nil, false
+	}
+
+	skipSpace := func() {
+		for i < n && isHTMLSpace(raw[i]) {
+			i++
+		}
+	}
+	for {
+		skipSpace()
+		if i >= n {
+			break
+		}
+		// Dấu '=' ở đầu tên thuộc tính được tính là một phần của tên
+		attr := htmlAttr{start: i, quote: -1}
+		if raw[i] == '=' {
+			i++
+		}
+		for i < n && !isHTMLSpace(raw[i]) && raw[i] != '/' && raw[i] != '=' {
+			i++
+		}
+		attr.name = strings.ToLower(raw[attr.start:i])
+		attr.end = i
+		if i < n && raw[i] != '=' {
+			// Khoảng trắng hoặc '/' kết thúc tên thuộc tính
+			i++
+		}
+
+		skipSpace()
+		if i < n && raw[i] == '=' {
+			i++
+			skipSpace()
+			switch {
+			case i >= n:
+			case raw[i] == '"' || raw[i] == '\'':
+				closing := strings.IndexByte(raw[i+1:n], raw[i])
+				if closing < 0 {
+					return nil, false
+				}
+				attr.quote = i
+				i += closing + 2
+			default:
+				for i < n && !isHTMLSpace(raw[i]) {
+					i++
+				}
+			}
+			attr.end = i
+		}
+		// Thuộc tính có tên rỗng (ví dụ dấu '/' thừa) bị bỏ qua như trong tokenizer
+		if attr.name != "" {
+			t.attrs = append(t.attrs, attr)
+		}
+	}
+	return t, true
+}
+
+// caseSlot - Tên thẻ có chữ cái ASCII và viết toàn chữ thường hoặc toàn chữ hoa.
+// Trình duyệt chỉ đổi chữ ASCII khi so tên thẻ nên ký tự khác được giữ nguyên.
+func (t *htmlTag) caseSlot() bool {
+	name := t.raw[t.nameStart:t.nameEnd]
+	lower, upper := asciiLower(name), asciiUpper(name)
+	return lower != upper && (name == lower || name == upper)
+}
+
+func (t *htmlTag) caseValue() byte {
+	name := t.raw[t.nameStart:t.nameEnd]
+	if name != asciiLower(name) {
+		return 1
+	}
+	return 0
+}
+
+// canonical - Chỉ số các thuộc tính sắp theo tên, là thứ tự ứng với hoán vị đồng nhất
+func (t *htmlTag) canonical() []int {
+	indices := make([]int, len(t.attrs))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		return t.attrs[indices[a]].name < t.attrs[indices[b]].name
+	})
+	return indices
+}
+
+// order - Hoán vị hiện tại: phần tử thứ i là vị trí trong thứ tự chuẩn của thuộc tính thứ i
+func (t *htmlTag) order() []int {
+	order := make([]int, len(t.attrs))
+	for rank, i := range t.canonical() {
+		order[i] = rank
+	}
+	return order
+}
+
+// orderBits - Số bit mang bởi thứ tự thuộc tính. Thẻ đóng và thẻ có thuộc tính trùng tên
+// không mang tin vì với thuộc tính trùng, trình duyệt chỉ giữ thuộc tính đầu tiên.
+func (t *htmlTag) orderBits() int {
+	if t.end {
+		return 0
+	}
+	seen := make(map[string]bool, len(t.attrs))
+	for _, attr := range t.attrs {
+		if seen[attr.name] {
+			return 0
+		}
+		seen[attr.name] = true
+	}
+	if len(t.attrs) < 2 || !t.reorderable() {
+		return 0
+	}
+	return bitutil.PermutationBits(len(t.attrs))
+}
+
+// reorderable - Đổi chỗ các thuộc tính không làm thay đổi cách tách thẻ: giữa hai thuộc
+// tính luôn có ngăn cách, giá trị không dấu nháy không thể nuốt dấu '/' của thẻ tự đóng
+// hay tự tạo ra thẻ tự đóng khi bị chuyển xuống cuối, và tên bắt đầu bằng '=' không bị
+// đặt sau dấu '/' (khi đó tokenizer đọc nó thành giá trị của một thuộc tính rỗng).
+func (t *htmlTag) reorderable() bool {
+	last := t.attrs[len(t.attrs)-1]
+	selfClosing := strings.HasPrefix(t.raw[last.end:], "/")
+	for i, attr := range t.attrs {
+		if i > 0 && t.attrs[i-1].end == attr.start || t.raw[attr.start] == '=' {
+			return false
+		}
+		unquoted := attr.quote < 0 && strings.Contains(t.raw[attr.start:attr.end], "=")
+		if unquoted && (selfClosing || strings.HasSuffix(t.raw[attr.start:attr.end], "/")) {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteSlots - Các thuộc tính mang tin bằng dấu nháy, theo thứ tự chuẩn để không phụ thuộc
+// vào thứ tự thuộc tính. Giá trị chứa dấu nháy không đổi được kiểu nháy nên bị bỏ qua.
+func (t *htmlTag) quoteSlots() []int {
+	if t.end {
+		return nil
+	}
+	var slots []int
+	for _, i := range t.canonical() {
+		attr := t.attrs[i]
+		if attr.quote < 0 {
+			continue
+		}
+		value := t.raw[attr.quote+1 : attr.end-1]
+		if !strings.ContainsAny(value, `"'`) {
+			slots = append(slots, i)
+		}
+	}
+	return slots
+}
+
+func (t *htmlTag) quoteValue(i int) byte {
+	if t.raw[t.attrs[i].quote] == '\'' {
+		return 1
+	}
+	return 0
+}
+
+// render - Ghi lại thẻ với kiểu chữ của tên, thứ tự thuộc tính và kiểu nháy mới.
+// Khoảng trắng giữa các thuộc tính được giữ nguyên tại chỗ.
+func (t *htmlTag) render(caseValue byte, order []int, quotes map[int]byte) string {
+	var sb strings.Builder
+	sb.Grow(len(t.raw))
+	sb.WriteString(t.raw[:t.nameStart])
+	name := t.raw[t.nameStart:t.nameEnd]
+	if t.caseSlot() && caseValue == 1 {
+		sb.WriteString(asciiUpper(name))
+	} else if t.caseSlot() {
+		sb.WriteString(asciiLower(name))
+	} else {
+		sb.WriteString(name)
+	}
+	if len(t.attrs) == 0 {
+		sb.WriteString(t.raw[t.nameEnd:])
+		return sb.String()
+	}
+
+	// Thuộc tính đứng ở vị trí thứ rank trong thứ tự chuẩn
+	byRank := t.canonical()
+	prev := t.nameEnd
+	for pos, attr := range t.attrs {
+		sb.WriteString(t.raw[prev:attr.start])
+		prev = attr.end
+
+		i := byRank[order[pos]]
+		src := t.attrs[i]
+		text := t.raw[src.start:src.end]
+		if value, ok := quotes[i]; ok {
+			quote := "\""
+			if value == 1 {
+				quote = "'"
+			}
+			q := src.quote - src.start
+			text = text[:q] + quote + text[q+1:len(text)-1] + quote
+		}
+		sb.WriteString(text)
+	}
+	sb.WriteString(t.raw[prev:])
+	return sb.String()
+}
+
+func asciiLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+func asciiUpper(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' {
+			return r - ('a' - 'A')
+		}
+		return r
+	}, s)
+}
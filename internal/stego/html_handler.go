@@ -0,0 +1,67 @@
+package stego
+
+import (
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// HTMLFields - Các lớp vật mang dùng cho request. Khi trích xuất phải dùng đúng các lớp
+// đã dùng khi giấu tin; để trống là bật tất cả.
+type HTMLFields struct {
+	Classes []string `json:"classes" binding:"omitempty,dive,oneof=attribute-order quotes case whitespace entities"`
+}
+
+// method - Tạo phương pháp theo các lớp trong request
+func (f *HTMLFields) method() (*HTML, error) {
+	classes, err := ParseHTMLClasses(f.Classes)
+	if err != nil {
+		return nil, err
+	}
+	return NewHTML(classes), nil
+}
+
+// HTMLEmbedRequest - Request body cho giấu tin trong mã nguồn HTML
+type HTMLEmbedRequest struct {
+	EmbedFields
+	HTMLFields
+}
+
+// HTMLExtractRequest - Request body cho trích xuất tin từ mã nguồn HTML
+type HTMLExtractRequest struct {
+	ExtractFields
+	HTMLFields
+}
+
+// HTMLEmbed - Giấu message vào tài liệu HTML mà không thay đổi trang hiển thị
+func (h *Handler) HTMLEmbed(c *gin.Context) {
+	var req HTMLEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodHTML))
+	respondEmbedded(c, text, err)
+}
+
+// HTMLExtract - Trích xuất message từ tài liệu HTML
+func (h *Handler) HTMLExtract(c *gin.Context) {
+	var req HTMLExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	result, err := h.service.ExtractWithMethod(method, req.Text, req.options(MethodHTML))
+	respondExtracted(c, result, err)
+}
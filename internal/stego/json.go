@@ -0,0 +1,597 @@
+package stego
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+)
+
+// Tên và ID của phương pháp giấu tin trong tài liệu JSON
+const (
+	MethodJSON   = "json"
+	methodIDJSON = 16
+)
+
+// Tên các lớp vật mang của phương pháp JSON
+const (
+	JSONKeyOrder = "key-order"
+	JSONIndent   = "indent"
+	JSONNumbers  = "numbers"
+)
+
+// Các lỗi của phương pháp JSON và YAML
+var (
+	ErrInvalidJSON = errors.New("cover is not a valid JSON document")
+	// ErrDocumentValueChanged - Tài liệu sau khi giấu tin giải mã ra giá trị khác văn bản phủ
+	ErrDocumentValueChanged = errors.New("hiding data would change the decoded document")
+)
+
+// JSONClasses - Các lớp vật mang được bật. Mọi lớp đều chỉ đổi cách viết, tài liệu giải mã
+// ra cùng một giá trị.
+type JSONClasses struct {
+	// KeyOrder - Thứ tự các khóa trong object, floor(log2(n!)) bit cho n khóa
+	KeyOrder bool
+	// Indent - Thụt lề 2 (0) hoặc 4 (1) dấu cách, 1 bit cho tài liệu đã định dạng nhiều dòng
+	Indent bool
+	// Numbers - Số nguyên viết 1 (0) hoặc 1.0 (1)
+	Numbers bool
+}
+
+// AllJSONClasses - Bật tất cả các lớp vật mang
+var AllJSONClasses = JSONClasses{KeyOrder: true, Indent: true, Numbers: true}
+
+// ParseJSONClasses - Chuyển danh sách tên lớp thành JSONClasses, rỗng là bật tất cả
+func ParseJSONClasses(names []string) (JSONClasses, error) {
+	if len(names) == 0 {
+		return AllJSONClasses, nil
+	}
+	var classes JSONClasses
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case JSONKeyOrder:
+			classes.KeyOrder = true
+		case JSONIndent:
+			classes.Indent = true
+		case JSONNumbers:
+			classes.Numbers = true
+		default:
+			return classes, fmt.Errorf("unknown json class %q", name)
+		}
+	}
+	return classes, nil
+}
+
+// jsonIntegerNumber - Số nguyên không có phần mũ, có thể kèm phần thập phân .0
+var jsonIntegerNumber = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)(?:\.0)?$`)
+
+// JSON - Giấu dữ liệu trong tài liệu JSON bằng thứ tự khóa, độ rộng thụt lề và cách viết số.
+// Tài liệu kết quả được giải mã và so với văn bản phủ trước khi trả về.
+type JSON struct {
+	Classes JSONClasses
+}
+
+// NewJSON - Tạo phương pháp JSON với các lớp vật mang đã chọn
+func NewJSON(classes JSONClasses) *JSON {
+	return &JSON{Classes: classes}
+}
+
+// ID - ID phương pháp trong header container
+func (j *JSON) ID() uint8 {
+	return methodIDJSON
+}
+
+// Name - Tên phương pháp
+func (j *JSON) Name() string {
+	return MethodJSON
+}
+
+// Capabilities - Đặc tính của phương pháp JSON.
+// Cắt khoảng trắng đầu dòng làm mất độ rộng thụt lề.
+func (j *JSON) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          !j.Classes.Indent,
+		SurvivesNormalization: true,
+		Structured:            true,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong tài liệu, 0 nếu không phải JSON hợp lệ
+func (j *JSON) Capacity(cover string) int {
+	doc, err := parseJSON(cover)
+	if err != nil {
+		return 0
+	}
+	return slotBits(j.slots(doc))
+}
+
+// Embed - Viết lại các vật mang theo từng nhóm bit của data, các vật mang sau phần dữ liệu
+// được giữ nguyên
+func (j *JSON) Embed(cover string, data []byte) (string, error) {
+	if strings.TrimSpace(cover) == "" {
+		return "", ErrEmptyCover
+	}
+	doc, err := parseJSON(cover)
+	if err != nil {
+		return "", err
+	}
+	slots := j.slots(doc)
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > slotBits(slots) {
+		return "", ErrCoverTooSmall
+	}
+	writeSlots(slots, bits)
+
+	text := doc.String()
+	if !sameJSONValue(cover, text) {
+		return "", ErrDocumentValueChanged
+	}
+	// Đọc lại để chắc chắn các vật mang không ảnh hưởng lẫn nhau
+	check, err := parseJSON(text)
+	if err != nil || !hasBitsPrefix(readSlots(j.slots(check)), bits) {
+		return "", ErrDocumentValueChanged
+	}
+	return text, nil
+}
+
+// Extract - Đọc bit từ các vật mang theo thứ tự trong tài liệu. Văn bản không phải JSON
+// được coi là không mang dữ liệu để việc tự nhận diện phương pháp thử phương pháp khác.
+func (j *JSON) Extract(text string) ([]byte, error) {
+	doc, err := parseJSON(text)
+	if err != nil {
+		return nil, ErrNoHiddenData
+	}
+	return bitutil.BitsToBytes(readSlots(j.slots(doc))), nil
+}
+
+// slots - Các vật mang theo thứ tự: độ rộng thụt lề, rồi duyệt cây giá trị. Khóa của object
+// được duyệt theo thứ tự chuẩn để không phụ thuộc vào thứ tự khóa đang mang.
+func (j *JSON) slots(doc *jsonDocument) []structSlot {
+	var slots []structSlot
+	if unit := doc.indentUnit(); j.Classes.Indent && unit > 0 {
+		slots = append(slots, structSlot{
+			bits:  1,
+			value: uint64(unit / 4),
+			write: func(value uint64) {
+				doc.unit = 2 + 2*int(value)
+			},
+		})
+	}
+
+	var walk func(v *jsonValue)
+	walk = func(v *jsonValue) {
+		switch v.kind {
+		case '{':
+			if n := v.orderBits(); j.Classes.KeyOrder && n > 0 {
+				slots = append(slots, structSlot{
+					bits:  n,
+					value: bitutil.PermutationIndex(v.order),
+					write: func(value uint64) {
+						v.order = bitutil.PermutationFromIndex(len(v.items), value)
+					},
+				})
+			}
+			for _, i := range v.canonical() {
+				walk(v.items[i].value)
+			}
+		case '[':
+			for _, item := range v.items {
+				walk(item.value)
+			}
+		case 'n':
+			if !j.Classes.Numbers || !jsonIntegerNumber.MatchString(v.raw) {
+				return
+			}
+			integer := strings.TrimSuffix(v.raw, ".0")
+			var current uint64
+			if integer != v.raw {
+				current = 1
+			}
+			slots = append(slots, structSlot{
+				bits:  1,
+				value: current,
+				write: func(value uint64) {
+					v.raw = integer
+					if value == 1 {
+						v.raw += ".0"
+					}
+				},
+			})
+		}
+	}
+	walk(doc.root)
+	return slots
+}
+
+// jsonDocument - Tài liệu JSON giữ nguyên mọi khoảng trắng để ghi lại đúng từng byte
+type jsonDocument struct {
+	root       *jsonValue
+	lead, tail string
+	// unit - Độ rộng thụt lề khi ghi lại, 0 là giữ nguyên khoảng trắng
+	unit int
+}
+
+// jsonValue - Một giá trị JSON. Với object và array, khoảng trắng quanh mỗi phần tử
+// thuộc về vị trí (lead[i] sau '{' hoặc ',', trail[i] trước ',' hoặc '}') chứ không thuộc
+// về phần tử, nhờ vậy đổi thứ tự khóa không làm xô lệch định dạng.
+type jsonValue struct {
+	// kind - '{', '[', 's' (chuỗi), 'n' (số) hoặc 'l' (true, false, null)
+	kind byte
+	raw  string
+	// inner - Khoảng trắng bên trong object/array rỗng
+	inner       string
+	lead, trail []string
+	items       []jsonItem
+	// order - Phần tử thứ i là vị trí trong thứ tự chuẩn của khóa đứng ở vị trí i
+	order []int
+}
+
+// jsonItem - Phần tử của object hoặc array. Với object, colon là đoạn từ sau khóa tới trước
+// giá trị (khoảng trắng và dấu ':').
+type jsonItem struct {
+	keyRaw string
+	key    string
+	colon  string
+	value  *jsonValue
+}
+
+// parseJSON - Phân tích tài liệu JSON hợp lệ thành cây giá trị
+func parseJSON(doc string) (*jsonDocument, error) {
+	if !json.Valid([]byte(doc)) {
+		return nil, ErrInvalidJSON
+	}
+	p := &jsonParser{src: doc}
+	d := &jsonDocument{lead: p.space()}
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	d.root = root
+	d.tail = p.space()
+	if p.pos != len(doc) {
+		return nil, ErrInvalidJSON
+	}
+	return d, nil
+}
+
+// jsonParser - Bộ phân tích đệ quy trên tài liệu đã được json.Valid kiểm tra
+type jsonParser struct {
+	src string
+	pos int
+}
+
+func (p *jsonParser) space() string {
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *jsonParser) value() (*jsonValue, error) {
+	if p.pos >= len(p.src) {
+		return nil, ErrInvalidJSON
+	}
+	switch c := p.src[p.pos]; {
+	case c == '{' || c == '[':
+		return p.container(c)
+	case c == '"':
+		raw, err := p.str()
+		return &jsonValue{kind: 's', raw: raw}, err
+	default:
+		start := p.pos
+		for p.pos < len(p.src) && strings.IndexByte(" \t\r\n,:]}", p.src[p.pos]) < 0 {
+			p.pos++
+		}
+		kind := byte('n')
+		if c == 't' || c == 'f' || c == 'n' {
+			kind = 'l'
+		}
+		return &jsonValue{kind: kind, raw: p.src[start:p.pos]}, nil
+	}
+}
+
+func (p *jsonParser) str() (string, error) {
+	start := p.pos
+	for p.pos++; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			return p.src[start:p.pos], nil
+		}
+	}
+	return "", ErrInvalidJSON
+}
+
+func (p *jsonParser) container(open byte) (*jsonValue, error) {
+	v := &jsonValue{kind: open}
+	closing := byte(']')
+	if open == '{' {
+		closing = '}'
+	}
+	p.pos++
+	space := p.space()
+	if p.pos < len(p.src) && p.src[p.pos] == closing {
+		p.pos++
+		v.inner = space
+		return v, nil
+	}
+	for {
+		v.lead = append(v.lead, space)
+		var item jsonItem
+		if open == '{' {
+			raw, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal([]byte(raw), &item.key); err != nil {
+				return nil, ErrInvalidJSON
+			}
+			item.keyRaw = raw
+			start := p.pos
+			p.space()
+			p.pos++ // ':'
+			p.space()
+			item.colon = p.src[start:p.pos]
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		item.value = value
+		v.items = append(v.items, item)
+		v.trail = append(v.trail, p.space())
+		if p.pos >= len(p.src) {
+			return nil, ErrInvalidJSON
+		}
+		p.pos++
+		if p.src[p.pos-1] == closing {
+			break
+		}
+		space = p.space()
+	}
+	if open == '{' {
+		v.order = make([]int, len(v.items))
+		for rank, i := range v.canonical() {
+			v.order[i] = rank
+		}
+	}
+	return v, nil
+}
+
+// canonical - Chỉ số các khóa sắp theo giá trị khóa, là thứ tự ứng với hoán vị đồng nhất
+func (v *jsonValue) canonical() []int {
+	indices := make([]int, len(v.items))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		return v.items[indices[a]].key < v.items[indices[b]].key
+	})
+	return indices
+}
+
+// orderBits - Số bit mang bởi thứ tự khóa. Object có khóa trùng không mang tin vì khi giải mã
+// chỉ giá trị cuối cùng được giữ lại.
+func (v *jsonValue) orderBits() int {
+	seen := make(map[string]bool, len(v.items))
+	for _, item := range v.items {
+		if seen[item.key] {
+			return 0
+		}
+		seen[item.key] = true
+	}
+	return bitutil.PermutationBits(len(v.items))
+}
+
+// indentUnit - Độ rộng thụt lề (2 hoặc 4) nếu mọi xuống dòng trong tài liệu đều được thụt
+// đúng theo độ sâu, 0 nếu tài liệu không được định dạng như vậy
+func (d *jsonDocument) indentUnit() int {
+	unit := 0
+	ok := true
+	check := func(space string, depth int) {
+		if !ok || !strings.Contains(space, "\n") {
+			return
+		}
+		indent := strings.TrimPrefix(space, "\n")
+		if strings.Trim(indent, " ") != "" || depth > 0 && len(indent)%depth != 0 {
+			ok = false
+			return
+		}
+		if depth == 0 {
+			ok = indent == ""
+			return
+		}
+		if unit == 0 {
+			unit = len(indent) / depth
+		}
+		ok = len(indent) == unit*depth
+	}
+	var walk func(v *jsonValue, depth int)
+	walk = func(v *jsonValue, depth int) {
+		if v.kind != '{' && v.kind != '[' {
+			return
+		}
+		check(v.inner, depth)
+		for i, item := range v.items {
+			check(v.lead[i], depth+1)
+			if strings.Contains(item.colon, "\n") {
+				ok = false
+			}
+			walk(item.value, depth+1)
+			if i == len(v.items)-1 {
+				check(v.trail[i], depth)
+			} else {
+				check(v.trail[i], depth+1)
+			}
+		}
+	}
+	check(d.lead, 0)
+	walk(d.root, 0)
+	check(d.tail, 0)
+	if !ok || unit != 2 && unit != 4 {
+		return 0
+	}
+	return unit
+}
+
+// String - Ghi lại tài liệu với thứ tự khóa, cách viết số và độ rộng thụt lề hiện tại
+func (d *jsonDocument) String() string {
+	var sb strings.Builder
+	indent := func(space string, depth int) string {
+		if d.unit == 0 || !strings.Contains(space, "\n") {
+			return space
+		}
+		return "\n" + strings.Repeat(" ", depth*d.unit)
+	}
+	var write func(v *jsonValue, depth int)
+	write = func(v *jsonValue, depth int) {
+		if v.kind != '{' && v.kind != '[' {
+			sb.WriteString(v.raw)
+			return
+		}
+		closing := "]"
+		if v.kind == '{' {
+			closing = "}"
+		}
+		sb.WriteByte(v.kind)
+		if len(v.items) == 0 {
+			sb.WriteString(indent(v.inner, depth))
+			sb.WriteString(closing)
+			return
+		}
+		byRank := v.canonical()
+		for pos := range v.items {
+			if pos > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(indent(v.lead[pos], depth+1))
+			item := v.items[pos]
+			if v.kind == '{' {
+				item = v.items[byRank[v.order[pos]]]
+				sb.WriteString(item.keyRaw)
+				sb.WriteString(item.colon)
+			}
+			write(item.value, depth+1)
+			if pos == len(v.items)-1 {
+				sb.WriteString(indent(v.trail[pos], depth))
+			} else {
+				sb.WriteString(indent(v.trail[pos], depth+1))
+			}
+		}
+		sb.WriteString(closing)
+	}
+	sb.WriteString(indent(d.lead, 0))
+	write(d.root, 0)
+	sb.WriteString(indent(d.tail, 0))
+	return sb.String()
+}
+
+// sameJSONValue - Hai tài liệu giải mã ra cùng một giá trị. Số được so chính xác dưới dạng
+// phân số nên 1 và 1.0 bằng nhau nhưng các số lớn khác nhau không bị làm tròn thành một.
+func sameJSONValue(a, b string) bool {
+	decode := func(doc string) (interface{}, bool) {
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return nil, false
+		}
+		_, err := dec.Token()
+		return v, err == io.EOF
+	}
+	va, okA := decode(a)
+	vb, okB := decode(b)
+	return okA && okB && equalJSONValues(va, vb)
+}
+
+func equalJSONValues(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !equalJSONValues(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSONValues(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okX := new(big.Rat).SetString(a.String())
+		y, okY := new(big.Rat).SetString(b.String())
+		return okX && okY && x.Cmp(y) == 0
+	default:
+		return a == b
+	}
+}
+
+// structSlot - Vật mang trong tài liệu có cấu trúc (JSON, YAML): số bit, giá trị hiện tại và
+// hàm ghi giá trị mới vào cây tài liệu
+type structSlot struct {
+	bits  int
+	value uint64
+	write func(value uint64)
+}
+
+func slotBits(slots []structSlot) int {
+	bits := 0
+	for _, slot := range slots {
+		bits += slot.bits
+	}
+	return bits
+}
+
+// writeSlots - Ghi bits vào các vật mang theo thứ tự. Vật mang cuối có thể mang nhiều bit
+// hơn phần còn lại, phần thiếu được đệm bằng 0.
+func writeSlots(slots []structSlot, bits []byte) {
+	for _, slot := range slots {
+		if len(bits) == 0 {
+			return
+		}
+		chunk := make([]byte, slot.bits)
+		bits = bits[copy(chunk, bits):]
+		if value := bitutil.UintFromBits(chunk); value != slot.value {
+			slot.write(value)
+		}
+	}
+}
+
+// readSlots - Đọc bit của các vật mang theo thứ tự. Chỉ số hoán vị được ghi trong n bit thấp.
+func readSlots(slots []structSlot) []byte {
+	var bits []byte
+	for _, slot := range slots {
+		bits = bitutil.AppendUintBits(bits, slot.value, slot.bits)
+	}
+	return bits
+}
+
+func hasBitsPrefix(bits, prefix []byte) bool {
+	return len(bits) >= len(prefix) && bytes.Equal(bits[:len(prefix)], prefix)
+}
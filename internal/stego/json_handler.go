@@ -0,0 +1,67 @@
+package stego
+
+import (
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// JSONFields - Các lớp vật mang dùng cho request. Khi trích xuất phải dùng đúng các lớp
+// đã dùng khi giấu tin; để trống là bật tất cả.
+type JSONFields struct {
+	Classes []string `json:"classes" binding:"omitempty,dive,oneof=key-order indent numbers"`
+}
+
+// method - Tạo phương pháp theo các lớp trong request
+func (f *JSONFields) method() (*JSON, error) {
+	classes, err := ParseJSONClasses(f.Classes)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSON(classes), nil
+}
+
+// JSONEmbedRequest - Request body cho giấu tin trong tài liệu JSON
+type JSONEmbedRequest struct {
+	EmbedFields
+	JSONFields
+}
+
+// JSONExtractRequest - Request body cho trích xuất tin từ tài liệu JSON
+type JSONExtractRequest struct {
+	ExtractFields
+	JSONFields
+}
+
+// JSONEmbed - Giấu message vào tài liệu JSON mà không thay đổi giá trị giải mã
+func (h *Handler) JSONEmbed(c *gin.Context) {
+	var req JSONEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodJSON))
+	respondEmbedded(c, text, err)
+}
+
+// JSONExtract - Trích xuất message từ tài liệu JSON
+func (h *Handler) JSONExtract(c *gin.Context) {
+	var req JSONExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	result, err := h.service.ExtractWithMethod(method, req.Text, req.options(MethodJSON))
+	respondExtracted(c, result, err)
+}
@@ -0,0 +1,659 @@
+package stego
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Tên và ID của phương pháp giấu tin trong Markdown
+const (
+	MethodMarkdown   = "markdown"
+	methodIDMarkdown = 14
+)
+
+// Tên các lớp vật mang của phương pháp Markdown
+const (
+	MarkdownEmphasis = "emphasis"
+	MarkdownLists    = "lists"
+	MarkdownHeadings = "headings"
+	MarkdownLinks    = "links"
+	MarkdownBreaks   = "breaks"
+)
+
+// ErrMarkdownRenderingChanged - Văn bản sau khi giấu tin hiển thị khác văn bản phủ theo CommonMark
+var ErrMarkdownRenderingChanged = errors.New("hiding data would change the rendered markdown")
+
+// MarkdownClasses - Các lớp vật mang được bật. Mỗi vật mang là một cặp cú pháp tương đương
+// trong CommonMark và mang 1 bit.
+type MarkdownClasses struct {
+	// Emphasis - Nhấn mạnh bằng * (0) hoặc _ (1)
+	Emphasis bool
+	// Lists - Ký hiệu danh sách - hoặc + (0) và * (1), đổi cho cả danh sách
+	Lists bool
+	// Headings - Tiêu đề ATX "# ..." (0) hoặc setext gạch dưới (1), chỉ với cấp 1 và 2
+	Headings bool
+	// Links - Liên kết inline (0) hoặc liên kết tham chiếu (1)
+	Links bool
+	// Breaks - Ngắt dòng bằng hai dấu cách (0) hoặc dấu \ (1) ở cuối dòng
+	Breaks bool
+}
+
+// AllMarkdownClasses - Bật tất cả các lớp vật mang
+var AllMarkdownClasses = MarkdownClasses{Emphasis: true, Lists: true, Headings: true, Links: true, Breaks: true}
+
+// ParseMarkdownClasses - Chuyển danh sách tên lớp thành MarkdownClasses, rỗng là bật tất cả
+func ParseMarkdownClasses(names []string) (MarkdownClasses, error) {
+	if len(names) == 0 {
+		return AllMarkdownClasses, nil
+	}
+	var classes MarkdownClasses
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case MarkdownEmphasis:
+			classes.Emphasis = true
+		case MarkdownLists:
+			classes.Lists = true
+		case MarkdownHeadings:
+			classes.Headings = true
+		case MarkdownLinks:
+			classes.Links = true
+		case MarkdownBreaks:
+			classes.Breaks = true
+		default:
+			return classes, fmt.Errorf("unknown markdown class %q", name)
+		}
+	}
+	return classes, nil
+}
+
+var (
+	// markdownOrderedItem - Dòng bắt đầu bằng số thứ tự sẽ thành danh sách có thứ tự
+	markdownOrderedItem = regexp.MustCompile(`^\d{1,9}[.)](?:[ \t]|$)`)
+	// markdownEntity - Tham chiếu thực thể sẽ bị giải mã nếu ghi lại nguyên văn
+	markdownEntity = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]*);`)
+)
+
+// Markdown - Giấu dữ liệu bằng các cú pháp Markdown tương đương: ký hiệu nhấn mạnh, ký hiệu
+// danh sách, kiểu tiêu đề, kiểu liên kết và kiểu ngắt dòng. Bản hiển thị theo CommonMark
+// được so với văn bản phủ sau mỗi lần giấu tin.
+type Markdown struct {
+	Classes MarkdownClasses
+}
+
+// NewMarkdown - Tạo phương pháp Markdown với các lớp vật mang đã chọn
+func NewMarkdown(classes MarkdownClasses) *Markdown {
+	return &Markdown{Classes: classes}
+}
+
+// ID - ID phương pháp trong header container
+func (m *Markdown) ID() uint8 {
+	return methodIDMarkdown
+}
+
+// Name - Tên phương pháp
+func (m *Markdown) Name() string {
+	return MethodMarkdown
+}
+
+// Capabilities - Đặc tính của phương pháp Markdown.
+// Cắt khoảng trắng cuối dòng làm mất ngắt dòng bằng hai dấu cách.
+func (m *Markdown) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          !m.Classes.Breaks,
+		SurvivesNormalization: true,
+		Structured:            true,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong văn bản phủ
+func (m *Markdown) Capacity(cover string) int {
+	return len(m.slots([]byte(cover)))
+}
+
+// Embed - Đổi cú pháp của các vật mang theo từng bit của data
+func (m *Markdown) Embed(cover string, data []byte) (string, error) {
+	if strings.TrimSpace(cover) == "" {
+		return "", ErrEmptyCover
+	}
+	src := []byte(cover)
+	slots := m.slots(src)
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > len(slots) {
+		return "", ErrCoverTooSmall
+	}
+
+	labels := newMarkdownLabels(cover)
+	var edits []markdownEdit
+	var definitions []string
+	for i, bit := range bits {
+		slot := slots[i]
+		if slot.value == bit {
+			continue
+		}
+		if slot.link != nil {
+			label := labels.next()
+			edits = append(edits, markdownEdit{slot.link.start, slot.link.end, "[" + label + "]"})
+			definitions = append(definitions, markdownDefinition(label, slot.link.node))
+			continue
+		}
+		edits = append(edits, slot.flip...)
+	}
+
+	out, err := applyMarkdownEdits(src, edits)
+	if err != nil {
+		return "", err
+	}
+	if len(definitions) > 0 {
+		if !bytes.HasSuffix(out, []byte("\n")) {
+			out = append(out, '\n')
+		}
+		out = append(out, '\n')
+		out = append(out, strings.Join(definitions, "\n")+"\n"...)
+	}
+
+	same, err := sameMarkdownRendering(src, out)
+	if err != nil {
+		return "", err
+	}
+	if !same {
+		return "", ErrMarkdownRenderingChanged
+	}
+	return string(out), nil
+}
+
+// Extract - Đọc bit từ cú pháp của các vật mang theo thứ tự trong văn bản
+func (m *Markdown) Extract(text string) ([]byte, error) {
+	slots := m.slots([]byte(text))
+	bits := make([]byte, len(slots))
+	for i, slot := range slots {
+		bits[i] = slot.value
+	}
+	return bitutil.BitsToBytes(bits), nil
+}
+
+// Các loại vật mang, theo thứ tự ưu tiên khi hai vật mang cùng vị trí
+const (
+	markdownSlotHeading = iota
+	markdownSlotList
+	markdownSlotLink
+	markdownSlotEmphasis
+	markdownSlotBreak
+)
+
+// markdownEdit - Thay đoạn [start, end) của văn bản gốc bằng text
+type markdownEdit struct {
+	start, end int
+	text       string
+}
+
+// markdownSlot - Một vật mang: vị trí, loại, giá trị hiện tại và các thay đổi để đổi giá trị.
+// Liên kết inline cần thêm định nghĩa tham chiếu nên được xử lý riêng qua link.
+type markdownSlot struct {
+	pos   int
+	kind  int
+	value byte
+	flip  []markdownEdit
+	link  *markdownLink
+}
+
+// markdownLink - Liên kết inline sẽ được đổi thành liên kết tham chiếu: đoạn "(...)" sau
+// văn bản liên kết được thay bằng "[label]"
+type markdownLink struct {
+	start, end int
+	node       *ast.Link
+}
+
+// slots - Tìm các vật mang theo thứ tự trong văn bản. Điều kiện của mỗi vật mang không phụ
+// thuộc vào giá trị hiện tại để văn bản đã giấu tin có đúng các vật mang như văn bản phủ.
+func (m *Markdown) slots(src []byte) []markdownSlot {
+	doc := goldmark.DefaultParser().Parse(text.NewReader(src))
+	var slots []markdownSlot
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		var slot *markdownSlot
+		switch node := n.(type) {
+		case *ast.Heading:
+			if m.Classes.Headings {
+				slot = markdownHeadingSlot(src, node)
+			}
+		case *ast.List:
+			if m.Classes.Lists {
+				slot = markdownListSlot(src, node)
+			}
+		case *ast.Link:
+			if m.Classes.Links {
+				slot = markdownLinkSlot(src, node)
+			}
+		case *ast.Emphasis:
+			if m.Classes.Emphasis {
+				slot = markdownEmphasisSlot(src, node)
+			}
+		case *ast.Text:
+			if m.Classes.Breaks && node.HardLineBreak() {
+				slot = markdownBreakSlot(src, node)
+			}
+		case *ast.Image:
+			// Văn bản thay thế của ảnh không giữ định dạng nên không mang tin
+			return ast.WalkSkipChildren, nil
+		}
+		if slot != nil {
+			slots = append(slots, *slot)
+		}
+		return ast.WalkContinue, nil
+	})
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].pos != slots[j].pos {
+			return slots[i].pos < slots[j].pos
+		}
+		return slots[i].kind < slots[j].kind
+	})
+	return slots
+}
+
+// markdownHeadingSlot - Tiêu đề cấp 1 hoặc 2 ở cấp tài liệu, một dòng, không thụt lề, đứng
+// sau dòng trống và nội dung bắt đầu bằng chữ hoặc số (để dòng nội dung đứng riêng vẫn là
+// đoạn văn khi đổi sang setext)
+func markdownHeadingSlot(src []byte, node *ast.Heading) *markdownSlot {
+	if node.Level > 2 || node.Lines().Len() != 1 || node.Parent() == nil || node.Parent().Kind() != ast.KindDocument {
+		return nil
+	}
+	seg := node.Lines().At(0)
+	contentStart := seg.Start
+	contentEnd := seg.Start + len(bytes.TrimRight(seg.Value(src), " \t"))
+	content := src[contentStart:contentEnd]
+	lineStart := bytes.LastIndexByte(src[:contentStart], '\n') + 1
+	lineEnd := markdownLineEnd(src, contentEnd)
+
+	if len(content) == 0 || bytes.HasSuffix(content, []byte("#")) || bytes.HasSuffix(content, []byte("\\")) ||
+		markdownOrderedItem.Match(content) || bytes.IndexByte(src[lineStart:lineEnd], '\r') >= 0 {
+		return nil
+	}
+	if first, _ := utf8.DecodeRune(content); !unicode.IsLetter(first) && !unicode.IsDigit(first) {
+		return nil
+	}
+	if lineStart > 0 {
+		prevStart := bytes.LastIndexByte(src[:lineStart-1], '\n') + 1
+		if len(bytes.TrimSpace(src[prevStart:lineStart-1])) > 0 {
+			return nil
+		}
+	}
+
+	underline := byte('=')
+	if node.Level == 2 {
+		underline = '-'
+	}
+	if lineStart == contentStart {
+		// Setext: dòng tiếp theo chỉ gồm ký tự gạch dưới, không thụt lề
+		if lineEnd >= len(src) {
+			return nil
+		}
+		underlineEnd := markdownLineEnd(src, lineEnd+1)
+		marks := bytes.TrimRight(src[lineEnd+1:underlineEnd], " \t")
+		if len(marks) == 0 || len(bytes.Trim(marks, string(underline))) > 0 {
+			return nil
+		}
+		return &markdownSlot{
+			pos:   lineStart,
+			kind:  markdownSlotHeading,
+			value: 1,
+			flip: []markdownEdit{
+				{lineStart, lineStart, strings.Repeat("#", node.Level) + " "},
+				{contentEnd, underlineEnd, ""},
+			},
+		}
+	}
+
+	prefix := src[lineStart:contentStart]
+	hashes := bytes.TrimRight(prefix, " \t")
+	if len(hashes) != node.Level || len(bytes.Trim(hashes, "#")) > 0 || len(hashes) == len(prefix) {
+		return nil
+	}
+	width := max(3, utf8.RuneCount(content))
+	return &markdownSlot{
+		pos:  lineStart,
+		kind: markdownSlotHeading,
+		flip: []markdownEdit{
+			{lineStart, contentStart, ""},
+			{contentEnd, lineEnd, "\n" + strings.Repeat(string(underline), width)},
+		},
+	}
+}
+
+// markdownListSlot - Danh sách không thứ tự mà mọi mục đều bắt đầu bằng đoạn văn. Danh sách
+// đứng sát một danh sách khác không mang tin vì đổi ký hiệu có thể gộp hai danh sách.
+func markdownListSlot(src []byte, node *ast.List) *markdownSlot {
+	if node.IsOrdered() {
+		return nil
+	}
+	if _, ok := node.PreviousSibling().(*ast.List); ok {
+		return nil
+	}
+	if _, ok := node.NextSibling().(*ast.List); ok {
+		return nil
+	}
+
+	var markers []int
+	for item := node.FirstChild(); item != nil; item = item.NextSibling() {
+		first := item.FirstChild()
+		if first == nil || (first.Kind() != ast.KindParagraph && first.Kind() != ast.KindTextBlock) || first.Lines().Len() == 0 {
+			return nil
+		}
+		i := first.Lines().At(0).Start - 1
+		for i >= 0 && (src[i] == ' ' || src[i] == '\t') {
+			i--
+		}
+		if i < 0 || src[i] != node.Marker {
+			return nil
+		}
+		if i > 0 && src[i-1] != '\n' && src[i-1] != ' ' && src[i-1] != '\t' && src[i-1] != '>' {
+			return nil
+		}
+		markers = append(markers, i)
+	}
+	if len(markers) == 0 {
+		return nil
+	}
+
+	slot := &markdownSlot{pos: markers[0], kind: markdownSlotList}
+	replacement := "*"
+	if node.Marker == '*' {
+		slot.value = 1
+		replacement = "-"
+	}
+	for _, i := range markers {
+		slot.flip = append(slot.flip, markdownEdit{i, i + 1, replacement})
+	}
+	return slot
+}
+
+// markdownLinkSlot - Liên kết có văn bản bắt đầu và kết thúc bằng chữ thường, để tìm được
+// dấu ] trên văn bản gốc. Giá trị là 0 với liên kết inline, 1 với liên kết tham chiếu.
+func markdownLinkSlot(src []byte, node *ast.Link) *markdownSlot {
+	first, ok := node.FirstChild().(*ast.Text)
+	if !ok {
+		return nil
+	}
+	last, ok := node.LastChild().(*ast.Text)
+	if !ok {
+		return nil
+	}
+	open, close := first.Segment.Start-1, last.Segment.Stop
+	if open < 0 || close >= len(src) || src[open] != '[' || src[close] != ']' {
+		return nil
+	}
+
+	after := close + 1
+	if after < len(src) && src[after] == '(' {
+		end, ok := markdownInlineLinkEnd(src, after)
+		if !ok {
+			return nil
+		}
+		return &markdownSlot{
+			pos:  open,
+			kind: markdownSlotLink,
+			link: &markdownLink{start: after, end: end, node: node},
+		}
+	}
+
+	// Liên kết tham chiếu đầy đủ [text][label], rút gọn [text][] hoặc [text]
+	end := after
+	if after < len(src) && src[after] == '[' {
+		if label, ok := markdownLabelEnd(src, after); ok {
+			end = label
+		}
+	}
+	return &markdownSlot{
+		pos:   open,
+		kind:  markdownSlotLink,
+		value: 1,
+		flip:  []markdownEdit{{after, end, "(" + markdownLinkTarget(node) + ")"}},
+	}
+}
+
+// markdownEmphasisSlot - Nhấn mạnh có nội dung bắt đầu và kết thúc bằng chữ thường, không
+// chứa * hoặc _, và đứng giữa khoảng trắng hoặc dấu câu. Khi đó * và _ mở, đóng nhấn mạnh
+// giống hệt nhau.
+func markdownEmphasisSlot(src []byte, node *ast.Emphasis) *markdownSlot {
+	first, ok := node.FirstChild().(*ast.Text)
+	if !ok {
+		return nil
+	}
+	last, ok := node.LastChild().(*ast.Text)
+	if !ok {
+		return nil
+	}
+	level := node.Level
+	start, stop := first.Segment.Start, last.Segment.Stop
+	if start-level < 0 || stop+level > len(src) || bytes.ContainsAny(src[start:stop], "*_") {
+		return nil
+	}
+	opener, closer := src[start-level:start], src[stop:stop+level]
+	delimiter := opener[0]
+	if (delimiter != '*' && delimiter != '_') || len(bytes.Trim(opener, string(delimiter))) > 0 ||
+		len(bytes.Trim(closer, string(delimiter))) > 0 {
+		return nil
+	}
+	before, _ := utf8.DecodeLastRune(src[:start-level])
+	after, _ := utf8.DecodeRune(src[stop+level:])
+	if !markdownEmphasisBoundary(before) || !markdownEmphasisBoundary(after) {
+		return nil
+	}
+
+	slot := &markdownSlot{pos: start - level, kind: markdownSlotEmphasis}
+	replacement := strings.Repeat("_", level)
+	if delimiter == '_' {
+		slot.value = 1
+		replacement = strings.Repeat("*", level)
+	}
+	slot.flip = []markdownEdit{
+		{start - level, start, replacement},
+		{stop, stop + level, replacement},
+	}
+	return slot
+}
+
+// markdownEmphasisBoundary - Ký tự trước hoặc sau nhấn mạnh: đầu/cuối văn bản, khoảng trắng
+// hoặc dấu câu khác * và _
+func markdownEmphasisBoundary(r rune) bool {
+	if r == utf8.RuneError {
+		return true
+	}
+	if r == '*' || r == '_' {
+		return false
+	}
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// markdownBreakSlot - Ngắt dòng cứng bằng ít nhất hai dấu cách (0) hoặc dấu \ (1). Ký tự
+// ngay trước không được là khoảng trắng hay \ để hai cách viết hiển thị như nhau.
+func markdownBreakSlot(src []byte, node *ast.Text) *markdownSlot {
+	stop := node.Segment.Stop
+	end := bytes.IndexByte(src[stop:], '\n')
+	if stop == 0 || end < 0 {
+		return nil
+	}
+	end += stop
+	if prev := src[stop-1]; prev == ' ' || prev == '\t' || prev == '\\' {
+		return nil
+	}
+	marker := src[stop:end]
+	switch {
+	case string(marker) == "\\":
+		return &markdownSlot{pos: stop, kind: markdownSlotBreak, value: 1, flip: []markdownEdit{{stop, end, "  "}}}
+	case len(marker) >= 2 && len(bytes.Trim(marker, " ")) == 0:
+		return &markdownSlot{pos: stop, kind: markdownSlotBreak, flip: []markdownEdit{{stop, end, "\\"}}}
+	}
+	return nil
+}
+
+// markdownLineEnd - Vị trí ký tự xuống dòng đầu tiên từ i, hoặc cuối văn bản
+func markdownLineEnd(src []byte, i int) int {
+	if end := bytes.IndexByte(src[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(src)
+}
+
+// markdownInlineLinkEnd - Vị trí ngay sau dấu ) kết thúc phần "(đích "tiêu đề")" của liên
+// kết inline bắt đầu tại open
+func markdownInlineLinkEnd(src []byte, open int) (int, bool) {
+	i := open + 1
+	skipSpace := func() {
+		for i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\n') {
+			i++
+		}
+	}
+	skipSpace()
+	if i < len(src) && src[i] == '<' {
+		for i++; i < len(src) && src[i] != '>'; i++ {
+			if src[i] == '\n' || src[i] == '<' {
+				return 0, false
+			}
+			if src[i] == '\\' {
+				i++
+			}
+		}
+		i++
+	} else {
+		depth := 0
+		for ; i < len(src) && src[i] > ' '; i++ {
+			switch src[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth < 0 {
+				break
+			}
+		}
+	}
+
+	skipSpace()
+	if i < len(src) && (src[i] == '"' || src[i] == '\'' || src[i] == '(') {
+		closing := src[i]
+		if closing == '(' {
+			closing = ')'
+		}
+		for i++; i < len(src) && src[i] != closing; i++ {
+			if src[i] == '\\' {
+				i++
+			}
+		}
+		i++
+		skipSpace()
+	}
+	if i >= len(src) || src[i] != ')' {
+		return 0, false
+	}
+	return i + 1, true
+}
+
+// markdownLabelEnd - Vị trí ngay sau dấu ] của nhãn tham chiếu bắt đầu tại open
+func markdownLabelEnd(src []byte, open int) (int, bool) {
+	for i := open + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '[':
+			return 0, false
+		case ']':
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// markdownLinkTarget - Đích và tiêu đề của liên kết dạng "<đích> "tiêu đề"", các ký tự có
+// nghĩa trong từng phần được escape để giải mã lại đúng giá trị ban đầu
+func markdownLinkTarget(node *ast.Link) string {
+	target := "<" + markdownEscape(node.Destination, "<>\\") + ">"
+	if len(node.Title) > 0 {
+		target += ` "` + markdownEscape(node.Title, "\"\\") + `"`
+	}
+	return target
+}
+
+// markdownDefinition - Định nghĩa liên kết tham chiếu
+func markdownDefinition(label string, node *ast.Link) string {
+	return "[" + label + "]: " + markdownLinkTarget(node)
+}
+
+// markdownEscape - Thêm \ trước các ký tự trong special và trước & của tham chiếu thực thể
+func markdownEscape(value []byte, special string) string {
+	var sb strings.Builder
+	for i, c := range value {
+		if strings.IndexByte(special, c) >= 0 || (c == '&' && markdownEntity.Match(value[i:])) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// markdownLabels - Cấp nhãn số cho các định nghĩa tham chiếu mới. Nhãn không được xuất hiện
+// dưới dạng [nhãn] (kể cả có khoảng trắng quanh nhãn) ở bất kỳ đâu trong văn bản, nếu không
+// văn bản đó sẽ thành liên kết.
+type markdownLabels struct {
+	text string
+	n    int
+}
+
+func newMarkdownLabels(text string) *markdownLabels {
+	return &markdownLabels{text: text}
+}
+
+func (l *markdownLabels) next() string {
+	for {
+		l.n++
+		label := fmt.Sprint(l.n)
+		if !regexp.MustCompile(`\[\s*` + label + `\s*\]`).MatchString(l.text) {
+			return label
+		}
+	}
+}
+
+// applyMarkdownEdits - Áp dụng các thay đổi không chồng lên nhau vào văn bản gốc
+func applyMarkdownEdits(src []byte, edits []markdownEdit) ([]byte, error) {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	out := make([]byte, 0, len(src)+len(edits)*4)
+	prev := 0
+	for _, edit := range edits {
+		if edit.start < prev {
+			return nil, fmt.Errorf("%w: overlapping edits", ErrMarkdownRenderingChanged)
+		}
+		out = append(out, src[prev:edit.start]...)
+		out = append(out, edit.text...)
+		prev = edit.end
+	}
+	return append(out, src[prev:]...), nil
+}
+
+// sameMarkdownRendering - Hai văn bản có cùng bản HTML theo CommonMark không
+func sameMarkdownRendering(a, b []byte) (bool, error) {
+	var htmlA, htmlB bytes.Buffer
+	if err := goldmark.Convert(a, &htmlA); err != nil {
+		return false, err
+	}
+	if err := goldmark.Convert(b, &htmlB); err != nil {
+		return false, err
+	}
+	return bytes.Equal(htmlA.Bytes(), htmlB.Bytes()), nil
+}
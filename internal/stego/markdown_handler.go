@@ -0,0 +1,67 @@
+package stego
+
+import (
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MarkdownFields - Các lớp vật mang dùng cho request. Khi trích xuất phải dùng đúng các lớp
+// đã dùng khi giấu tin; để trống là bật tất cả.
+type MarkdownFields struct {
+	Classes []string `json:"classes" binding:"omitempty,dive,oneof=emphasis lists headings links breaks"`
+}
+
+// method - Tạo phương pháp theo các lớp trong request
+func (f *MarkdownFields) method() (*Markdown, error) {
+	classes, err := ParseMarkdownClasses(f.Classes)
+	if err != nil {
+		return nil, err
+	}
+	return NewMarkdown(classes), nil
+}
+
+// MarkdownEmbedRequest - Request body cho giấu tin trong văn bản Markdown
+type MarkdownEmbedRequest struct {
+	EmbedFields
+	MarkdownFields
+}
+
+// MarkdownExtractRequest - Request body cho trích xuất tin từ văn bản Markdown
+type MarkdownExtractRequest struct {
+	ExtractFields
+	MarkdownFields
+}
+
+// MarkdownEmbed - Giấu message vào văn bản Markdown mà không thay đổi bản hiển thị CommonMark
+func (h *Handler) MarkdownEmbed(c *gin.Context) {
+	var req MarkdownEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodMarkdown))
+	respondEmbedded(c, text, err)
+}
+
+// MarkdownExtract - Trích xuất message từ văn bản Markdown
+func (h *Handler) MarkdownExtract(c *gin.Context) {
+	var req MarkdownExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	result, err := h.service.ExtractWithMethod(method, req.Text, req.options(MethodMarkdown))
+	respondExtracted(c, result, err)
+}
@@ -0,0 +1,148 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/gosource"
+)
+
+// ErrUnknownMethod - Phương pháp giấu tin không tồn tại
+var ErrUnknownMethod = errors.New("unknown hiding method")
+
+// Capabilities - Các đặc tính của một phương pháp giấu tin
+type Capabilities struct {
+	// SurvivesTrim - Dữ liệu còn nguyên sau khi cắt khoảng trắng đầu/cuối dòng
+	SurvivesTrim bool `json:"survives_trim"`
+	// SurvivesNormalization - Dữ liệu còn nguyên sau khi chuẩn hóa Unicode (NFC/NFKC)
+	SurvivesNormalization bool `json:"survives_normalization"`
+	// BitsPerChar - Số bit trung bình mỗi ký tự mang tin
+	BitsPerChar float64 `json:"bits_per_char"`
+	// Structured - Văn bản phủ là tài liệu có cấu trúc (HTML, JSON, mã nguồn...), cắt ngang
+	// tài liệu không cho ra tài liệu hợp lệ
+	Structured bool `json:"structured"`
+}
+
+// Method - Interface cho một phương pháp giấu tin trong văn bản.
+// ID được ghi vào header container để nhận diện phương pháp khi trích xuất.
+// Capacity trả về số bit; Extract trả về toàn bộ các byte đọc được từ văn bản.
+type Method interface {
+	ID() uint8
+	Name() string
+	Capabilities() Capabilities
+	Capacity(cover string) int
+	Embed(cover string, data []byte) (string, error)
+	Extract(text string) ([]byte, error)
+}
+
+// Registry - Interface cho danh sách các phương pháp giấu tin
+type Registry interface {
+	Register(method Method) error
+	Get(name string) (Method, error)
+	List() []Method
+}
+
+// MethodRegistry - Triển khai Registry interface
+type MethodRegistry struct {
+	mu      sync.RWMutex
+	methods map[string]Method
+	order   []string
+}
+
+// NewRegistry - Tạo registry rỗng
+func NewRegistry() Registry {
+	return &MethodRegistry{
+		methods: make(map[string]Method),
+	}
+}
+
+// NewDefaultRegistry - Tạo registry với các phương pháp có sẵn theo cấu hình
+func NewDefaultRegistry(config Config) (Registry, error) {
+	homoglyph, err := NewHomoglyph(config.Homoglyph.Table, config.Homoglyph.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	synonyms := config.Synonyms
+	if synonyms == nil {
+		synonyms = BundledSynonyms()
+	}
+	vietnamese, err := synonyms.Get("vi")
+	if err != nil {
+		return nil, err
+	}
+	english, err := synonyms.Get("en")
+	if err != nil {
+		return nil, err
+	}
+
+	registry := NewRegistry()
+	for _, method := range []Method{
+		NewZeroWidth(),
+		NewWhitespace(),
+		homoglyph,
+		NewVariationSelector(),
+		NewToneMark(),
+		NewNormalization(),
+		NewSynonym(vietnamese),
+		NewSynonym(english),
+		NewSpacing(AllSpacingClasses),
+		NewHTML(AllHTMLClasses),
+		NewMarkdown(AllMarkdownClasses),
+		NewGoSource(gosource.AllChannels),
+		NewJSON(AllJSONClasses),
+		NewYAML(AllYAMLClasses),
+	} {
+		if err := registry.Register(method); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Register - Đăng ký phương pháp mới
+func (r *MethodRegistry) Register(method Method) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := method.Name()
+	if name == "" {
+		return errors.New("method name is required")
+	}
+	if _, exists := r.methods[name]; exists {
+		return fmt.Errorf("method %q already registered", name)
+	}
+	for _, m := range r.methods {
+		if m.ID() == method.ID() {
+			return fmt.Errorf("method id %d already used by %q", method.ID(), m.Name())
+		}
+	}
+	r.methods[name] = method
+	r.order = append(r.order, name)
+	return nil
+}
+
+// Get - Lấy phương pháp theo tên
+func (r *MethodRegistry) Get(name string) (Method, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	method, ok := r.methods[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMethod, name)
+	}
+	return method, nil
+}
+
+// List - Danh sách phương pháp theo thứ tự đăng ký
+func (r *MethodRegistry) List() []Method {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	methods := make([]Method, 0, len(r.order))
+	for _, name := range r.order {
+		methods = append(methods, r.methods[name])
+	}
+	return methods
+}
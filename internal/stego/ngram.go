@@ -0,0 +1,538 @@
+package stego
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed corpus/*.txt
+var bundledCorpora embed.FS
+
+// Tham số mặc định khi huấn luyện mô hình n-gram
+const (
+	DefaultNgramOrder = 3
+	DefaultNgramTopK  = 16
+
+	maxNgramOrder = 6
+	maxNgramTopK  = 256
+)
+
+// Định dạng file mô hình n-gram (big-endian, số nguyên không dấu dạng uvarint):
+//
+//	magic    [4]byte  "DHNG"
+//	version  uint8
+//	order    uint8
+//	vocab    uvarint số token, rồi mỗi token: uvarint độ dài + UTF-8
+//	contexts uvarint số ngữ cảnh, rồi mỗi ngữ cảnh:
+//	           uvarint số token ngữ cảnh + các ID token
+//	           uvarint số ứng viên + mỗi ứng viên: ID token, số lần xuất hiện
+//	crc32    uint32   CRC-32 (IEEE) của toàn bộ phần trước
+//
+// Token 0 là điểm bắt đầu đoạn văn và không bao giờ được sinh ra.
+const ngramModelVersion uint8 = 1
+
+var ngramModelMagic = [4]byte{'D', 'H', 'N', 'G'}
+
+// ngramPunctuation - Các dấu câu được coi là token riêng, viết liền sau từ đứng trước
+const ngramPunctuation = ".,!?;:"
+
+// ErrInvalidNgramModel - File mô hình không hợp lệ
+var ErrInvalidNgramModel = errors.New("invalid n-gram model")
+
+// ngramCandidate - Một token có thể theo sau ngữ cảnh và số lần xuất hiện trong corpus
+type ngramCandidate struct {
+	token uint32
+	count uint32
+}
+
+// NgramModel - Mô hình n-gram: với mỗi ngữ cảnh (tối đa Order-1 token trước), danh sách
+// các token theo sau nhiều nhất, sắp xếp theo số lần xuất hiện giảm dần.
+// Ngữ cảnh có ít hơn 2 ứng viên bị bỏ để khi sinh văn bản mỗi bước luôn có lựa chọn.
+type NgramModel struct {
+	Order int
+
+	// tokens - Dạng hiển thị của token (giữ chữ hoa như "I", tên riêng)
+	tokens   []string
+	ids      map[string]uint32
+	contexts map[string][]ngramCandidate
+	// fingerprint - SHA-256 của file mô hình, xác định mô hình khi làm trắng dữ liệu
+	fingerprint [sha256.Size]byte
+}
+
+// TrainNgramModel - Huấn luyện mô hình từ corpus: mỗi dòng là một đoạn văn,
+// dòng trống và dòng bắt đầu bằng "#" được bỏ qua
+func TrainNgramModel(r io.Reader, order, topK int) (*NgramModel, error) {
+	if order < 1 || order > maxNgramOrder {
+		return nil, fmt.Errorf("n-gram order must be between 1 and %d", maxNgramOrder)
+	}
+	if topK < 2 || topK > maxNgramTopK {
+		return nil, fmt.Errorf("top-k must be between 2 and %d", maxNgramTopK)
+	}
+
+	m := &NgramModel{
+		Order:    order,
+		tokens:   []string{""},
+		ids:      map[string]uint32{"": 0},
+		contexts: make(map[string][]ngramCandidate),
+	}
+	counts := make(map[string]map[uint32]uint32)
+	forms := make(map[uint32]map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		history := make([]uint32, order-1)
+		sentenceStart := true
+		for _, form := range splitNgramTokens(line) {
+			key := strings.ToLower(form)
+			id, ok := m.ids[key]
+			if !ok {
+				id = uint32(len(m.tokens))
+				m.ids[key] = id
+				m.tokens = append(m.tokens, key)
+			}
+			// Dạng chữ hoa đầu câu không tính là dạng hiển thị của từ
+			if !sentenceStart || isNgramPunctuation(key) {
+				if forms[id] == nil {
+					forms[id] = make(map[string]int)
+				}
+				forms[id][form]++
+			}
+			sentenceStart = strings.Contains(".!?", key)
+
+			for n := 0; n < order; n++ {
+				ctx := ngramContextKey(history[len(history)-n:])
+				if counts[ctx] == nil {
+					counts[ctx] = make(map[uint32]uint32)
+				}
+				counts[ctx][id]++
+			}
+			if order > 1 {
+				history = append(history[1:], id)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for id, variants := range forms {
+		best, bestCount := m.tokens[id], 0
+		for form, count := range variants {
+			if count > bestCount || (count == bestCount && form < best) {
+				best, bestCount = form, count
+			}
+		}
+		m.tokens[id] = best
+	}
+	for ctx, next := range counts {
+		candidates := make([]ngramCandidate, 0, len(next))
+		for token, count := range next {
+			candidates = append(candidates, ngramCandidate{token: token, count: count})
+		}
+		sortNgramCandidates(candidates)
+		if len(candidates) > topK {
+			candidates = candidates[:topK]
+		}
+		if len(candidates) >= 2 {
+			m.contexts[ctx] = candidates
+		}
+	}
+	if len(m.contexts[""]) < 2 {
+		return nil, errors.New("corpus is too small to train an n-gram model")
+	}
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	m.fingerprint = sha256.Sum256(buf.Bytes())
+	return m, nil
+}
+
+// sortNgramCandidates - Sắp xếp theo số lần xuất hiện giảm dần, cùng số lần thì theo ID
+func sortNgramCandidates(candidates []ngramCandidate) {
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].count != candidates[j].count {
+			return candidates[i].count > candidates[j].count
+		}
+		return candidates[i].token < candidates[j].token
+	})
+}
+
+// WriteTo - Ghi mô hình theo định dạng file mô hình n-gram
+func (m *NgramModel) WriteTo(w io.Writer) (int64, error) {
+	buf := append([]byte{}, ngramModelMagic[:]...)
+	buf = append(buf, ngramModelVersion, byte(m.Order))
+	buf = binary.AppendUvarint(buf, uint64(len(m.tokens)))
+	for _, token := range m.tokens {
+		buf = binary.AppendUvarint(buf, uint64(len(token)))
+		buf = append(buf, token...)
+	}
+
+	keys := make([]string, 0, len(m.contexts))
+	for ctx := range m.contexts {
+		keys = append(keys, ctx)
+	}
+	sort.Strings(keys)
+	buf = binary.AppendUvarint(buf, uint64(len(keys)))
+	for _, ctx := range keys {
+		ids := parseNgramContextKey(ctx)
+		buf = binary.AppendUvarint(buf, uint64(len(ids)))
+		for _, id := range ids {
+			buf = binary.AppendUvarint(buf, uint64(id))
+		}
+		candidates := m.contexts[ctx]
+		buf = binary.AppendUvarint(buf, uint64(len(candidates)))
+		for _, candidate := range candidates {
+			buf = binary.AppendUvarint(buf, uint64(candidate.token))
+			buf = binary.AppendUvarint(buf, uint64(candidate.count))
+		}
+	}
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ReadNgramModel - Đọc mô hình đã ghi bởi WriteTo
+func ReadNgramModel(r io.Reader) (*NgramModel, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(ngramModelMagic)+6 || !bytes.Equal(data[:len(ngramModelMagic)], ngramModelMagic[:]) {
+		return nil, ErrInvalidNgramModel
+	}
+	body := data[:len(data)-4]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(data[len(data)-4:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidNgramModel)
+	}
+	if body[4] != ngramModelVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidNgramModel, body[4])
+	}
+
+	m := &NgramModel{
+		Order:       int(body[5]),
+		ids:         make(map[string]uint32),
+		contexts:    make(map[string][]ngramCandidate),
+		fingerprint: sha256.Sum256(data),
+	}
+	if m.Order < 1 || m.Order > maxNgramOrder {
+		return nil, fmt.Errorf("%w: order %d", ErrInvalidNgramModel, m.Order)
+	}
+	reader := &ngramModelReader{data: body[6:]}
+
+	vocab := reader.uvarint(1 << 24)
+	if vocab == 0 {
+		return nil, ErrInvalidNgramModel
+	}
+	for i := uint64(0); i < vocab && reader.err == nil; i++ {
+		token := reader.bytes()
+		m.ids[strings.ToLower(string(token))] = uint32(i)
+		m.tokens = append(m.tokens, string(token))
+	}
+	contexts := reader.uvarint(1 << 26)
+	for i := uint64(0); i < contexts && reader.err == nil; i++ {
+		ids := make([]uint32, reader.uvarint(uint64(m.Order-1)))
+		for j := range ids {
+			ids[j] = uint32(reader.uvarint(vocab - 1))
+		}
+		candidates := make([]ngramCandidate, reader.uvarint(maxNgramTopK))
+		for j := range candidates {
+			candidates[j].token = uint32(reader.uvarint(vocab - 1))
+			candidates[j].count = uint32(reader.uvarint(1<<32 - 1))
+			if candidates[j].token == 0 || candidates[j].count == 0 {
+				reader.fail()
+			}
+		}
+		if len(candidates) < 2 {
+			reader.fail()
+		}
+		m.contexts[ngramContextKey(ids)] = candidates
+	}
+	if reader.err == nil && len(reader.data) != 0 {
+		reader.fail()
+	}
+	if reader.err != nil {
+		return nil, reader.err
+	}
+	if len(m.tokens) == 0 || m.tokens[0] != "" || len(m.contexts[""]) < 2 {
+		return nil, ErrInvalidNgramModel
+	}
+	return m, nil
+}
+
+// ngramModelReader - Đọc tuần tự các trường của file mô hình, lỗi đầu tiên được giữ lại
+type ngramModelReader struct {
+	data []byte
+	err  error
+}
+
+func (r *ngramModelReader) fail() {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: malformed data", ErrInvalidNgramModel)
+	}
+}
+
+func (r *ngramModelReader) uvarint(max uint64) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 || v > max {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *ngramModelReader) bytes() []byte {
+	n := r.uvarint(uint64(len(r.data)))
+	if r.err != nil {
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+// NgramModelInfo - Thông tin một mô hình trong thư viện
+type NgramModelInfo struct {
+	Language   string `json:"language,omitempty"`
+	Order      int    `json:"order"`
+	Vocabulary int    `json:"vocabulary"`
+	Contexts   int    `json:"contexts"`
+}
+
+// Info - Thông tin thống kê của mô hình
+func (m *NgramModel) Info() NgramModelInfo {
+	return NgramModelInfo{
+		Order:      m.Order,
+		Vocabulary: len(m.tokens) - 1,
+		Contexts:   len(m.contexts),
+	}
+}
+
+// candidates - Các ứng viên của ngữ cảnh dài nhất khớp với history (lùi dần về ngữ cảnh ngắn hơn)
+func (m *NgramModel) candidates(history []uint32) []ngramCandidate {
+	for n := m.Order - 1; n > 0; n-- {
+		if candidates, ok := m.contexts[ngramContextKey(history[len(history)-n:])]; ok {
+			return candidates
+		}
+	}
+	return m.contexts[""]
+}
+
+// ngramFrequencies - Tần suất cộng dồn của các ứng viên, được thu nhỏ để tổng không vượt
+// maxFrequencyTotal và mỗi ứng viên có tần suất ít nhất 1
+func ngramFrequencies(candidates []ngramCandidate) []uint64 {
+	var total uint64
+	for _, candidate := range candidates {
+		total += uint64(candidate.count)
+	}
+	cum := make([]uint64, len(candidates)+1)
+	for i, candidate := range candidates {
+		freq := uint64(candidate.count)
+		if total > maxFrequencyTotal-uint64(len(candidates)) {
+			freq = freq * (maxFrequencyTotal - uint64(len(candidates))) / total
+		}
+		cum[i+1] = cum[i] + freq + 1
+	}
+	return cum
+}
+
+// ngramContextKey - Khóa map của một ngữ cảnh
+func ngramContextKey(ids []uint32) string {
+	var buf []byte
+	for _, id := range ids {
+		buf = binary.AppendUvarint(buf, uint64(id))
+	}
+	return string(buf)
+}
+
+func parseNgramContextKey(key string) []uint32 {
+	var ids []uint32
+	data := []byte(key)
+	for len(data) > 0 {
+		id, n := binary.Uvarint(data)
+		ids = append(ids, uint32(id))
+		data = data[n:]
+	}
+	return ids
+}
+
+// splitNgramTokens - Tách văn bản thành các từ (chữ, số, dấu kết hợp) và dấu câu,
+// các ký tự khác được bỏ qua
+func splitNgramTokens(text string) []string {
+	text = norm.NFC.String(text)
+	var tokens []string
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		if inWord {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, text[start:i])
+			start = -1
+		}
+		if strings.ContainsRune(ngramPunctuation, r) {
+			tokens = append(tokens, string(r))
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+func isNgramPunctuation(token string) bool {
+	return len(token) == 1 && strings.Contains(ngramPunctuation, token)
+}
+
+// detokenize - Ghép token thành văn bản: dấu câu viết liền, viết hoa đầu câu
+func (m *NgramModel) detokenize(ids []uint32) string {
+	var sb strings.Builder
+	capitalize := true
+	for _, id := range ids {
+		token := m.tokens[id]
+		if isNgramPunctuation(token) {
+			sb.WriteString(token)
+			capitalize = capitalize || strings.Contains(".!?", token)
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		if capitalize {
+			r, n := utf8.DecodeRuneInString(token)
+			token = string(unicode.ToUpper(r)) + token[n:]
+			capitalize = false
+		}
+		sb.WriteString(token)
+	}
+	return sb.String()
+}
+
+// NgramLibrary - Các mô hình n-gram theo ngôn ngữ
+type NgramLibrary struct {
+	models map[string]*NgramModel
+}
+
+var (
+	bundledNgramsOnce sync.Once
+	bundledNgrams     *NgramLibrary
+)
+
+// BundledNgramModels - Thư viện các mô hình huấn luyện từ corpus đi kèm ứng dụng
+func BundledNgramModels() *NgramLibrary {
+	bundledNgramsOnce.Do(func() {
+		library := &NgramLibrary{models: make(map[string]*NgramModel)}
+		entries, err := fs.ReadDir(bundledCorpora, "corpus")
+		if err == nil {
+			for _, entry := range entries {
+				var file fs.File
+				file, err = bundledCorpora.Open(path.Join("corpus", entry.Name()))
+				if err != nil {
+					break
+				}
+				var model *NgramModel
+				model, err = TrainNgramModel(file, DefaultNgramOrder, DefaultNgramTopK)
+				file.Close()
+				if err != nil {
+					err = fmt.Errorf("%s: %w", entry.Name(), err)
+					break
+				}
+				library.models[strings.TrimSuffix(entry.Name(), ".txt")] = model
+			}
+		}
+		if err != nil {
+			// Corpus đi kèm được kiểm tra khi phát triển, lỗi ở đây là lỗi build
+			panic(fmt.Sprintf("invalid bundled corpus: %v", err))
+		}
+		bundledNgrams = library
+	})
+	return bundledNgrams
+}
+
+// LoadNgramLibrary - Thư viện gồm các mô hình đi kèm và các file <ngôn ngữ>.ngram trong dir
+// (tạo bằng lệnh ngram-train). File trong dir thay thế mô hình đi kèm cùng ngôn ngữ.
+func LoadNgramLibrary(dir string) (*NgramLibrary, error) {
+	library := &NgramLibrary{models: make(map[string]*NgramModel)}
+	for language, model := range BundledNgramModels().models {
+		library.models[language] = model
+	}
+	if dir == "" {
+		return library, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".ngram" {
+			continue
+		}
+		file, err := os.Open(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		model, err := ReadNgramModel(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		library.models[strings.ToLower(strings.TrimSuffix(entry.Name(), ".ngram"))] = model
+	}
+	return library, nil
+}
+
+// Get - Lấy mô hình theo mã ngôn ngữ
+func (l *NgramLibrary) Get(language string) (*NgramModel, error) {
+	model, ok := l.models[strings.ToLower(language)]
+	if !ok {
+		return nil, fmt.Errorf("no n-gram model for language %q", language)
+	}
+	return model, nil
+}
+
+// Models - Danh sách mô hình theo mã ngôn ngữ
+func (l *NgramLibrary) Models() []NgramModelInfo {
+	infos := make([]NgramModelInfo, 0, len(l.models))
+	for language, model := range l.models {
+		info := model.Info()
+		info.Language = language
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Language < infos[j].Language
+	})
+	return infos
+}
@@ -0,0 +1,186 @@
+package stego
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+)
+
+// Tên và ID của phương pháp sinh văn bản bằng mô hình n-gram
+const (
+	MethodNgram   = "ngram"
+	methodIDNgram = 11
+)
+
+// Giới hạn khi sinh văn bản
+const (
+	// ngramNonceSize - Số byte nonce (dẫn xuất từ dữ liệu) ghi trước dữ liệu đã làm trắng
+	ngramNonceSize = 8
+	// maxNgramTail - Số token tối đa sinh thêm để kết thúc câu sau khi đã hết dữ liệu
+	maxNgramTail = 40
+	// ngramTokensPerBit - Giới hạn số token trên mỗi bit, tránh lặp vô hạn với mô hình quá lệch
+	ngramTokensPerBit = 64
+)
+
+// ErrNgramModelTooPredictable - Mô hình gần như không có lựa chọn nên không mang được dữ liệu
+var ErrNgramModelTooPredictable = errors.New("n-gram model is too predictable to carry the payload")
+
+// Generator - Phương pháp sinh văn bản phủ mang dữ liệu thay vì sửa văn bản có sẵn
+type Generator interface {
+	ID() uint8
+	Name() string
+	// Generate - Sinh văn bản mang data; cùng data luôn cho cùng văn bản
+	Generate(data []byte) (string, error)
+	// Recover - Đọc lại dữ liệu từ văn bản đã sinh
+	Recover(text string) ([]byte, error)
+}
+
+// NgramGenerator - Sinh văn bản bằng cách giải mã số học dữ liệu thành các lựa chọn token
+// của mô hình n-gram. Trích xuất phát lại cùng mô hình để mã hóa lại các token thành bit.
+//
+// Dữ liệu được làm trắng trước khi sinh để header container (luôn giống nhau) không làm
+// mọi văn bản bắt đầu giống nhau: nonce = SHA-256(data)[:8], phần còn lại XOR với dòng
+// khóa SHA-256(fingerprint mô hình || nonce || bộ đếm).
+type NgramGenerator struct {
+	Model *NgramModel
+}
+
+// NewNgramGenerator - Tạo bộ sinh văn bản với một mô hình
+func NewNgramGenerator(model *NgramModel) *NgramGenerator {
+	return &NgramGenerator{Model: model}
+}
+
+// ID - ID phương pháp trong header container
+func (g *NgramGenerator) ID() uint8 {
+	return methodIDNgram
+}
+
+// Name - Tên phương pháp
+func (g *NgramGenerator) Name() string {
+	return MethodNgram
+}
+
+// Generate - Sinh văn bản mang data
+func (g *NgramGenerator) Generate(data []byte) (string, error) {
+	m := g.Model
+	digest := sha256.Sum256(data)
+	nonce := digest[:ngramNonceSize]
+	stream := g.keystream(nonce, len(data)+1024)
+	whitened := append([]byte{}, nonce...)
+	for i, b := range data {
+		whitened = append(whitened, b^stream[i])
+	}
+	bits := bitutil.BytesToBits(whitened)
+
+	// Sau khi hết dữ liệu, các bit đệm lấy tiếp từ dòng khóa để phần kết câu vẫn tự nhiên
+	// và không phụ thuộc vào gì ngoài dữ liệu
+	padding := bitutil.BytesToBits(stream[len(data):])
+	pos := 0
+	next := func() byte {
+		var bit byte
+		switch {
+		case pos < len(bits):
+			bit = bits[pos]
+		default:
+			bit = padding[(pos-len(bits))%len(padding)]
+		}
+		pos++
+		return bit
+	}
+
+	coder := newArithmeticDecoder(next)
+	history := make([]uint32, m.Order-1)
+	var ids []uint32
+	limit := len(bits)*ngramTokensPerBit + maxNgramTail
+	for len(coder.out) < len(bits) {
+		if len(ids) >= limit {
+			return "", ErrNgramModelTooPredictable
+		}
+		ids, history = g.step(coder, ids, history)
+	}
+	for tail := 0; tail < maxNgramTail && !g.sentenceEnded(ids); tail++ {
+		ids, history = g.step(coder, ids, history)
+	}
+	if !g.sentenceEnded(ids) {
+		if id, ok := m.ids["."]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return m.detokenize(ids), nil
+}
+
+// step - Chọn token tiếp theo theo giá trị hiện tại của bộ giải mã
+func (g *NgramGenerator) step(coder *arithmeticCoder, ids, history []uint32) ([]uint32, []uint32) {
+	candidates := g.Model.candidates(history)
+	id := candidates[coder.decode(ngramFrequencies(candidates))].token
+	ids = append(ids, id)
+	if len(history) > 0 {
+		history = append(history[1:], id)
+	}
+	return ids, history
+}
+
+// sentenceEnded - Văn bản đã kết thúc bằng dấu kết thúc câu
+func (g *NgramGenerator) sentenceEnded(ids []uint32) bool {
+	return len(ids) > 0 && strings.Contains(".!?", g.Model.tokens[ids[len(ids)-1]])
+}
+
+// Recover - Mã hóa lại các token của văn bản để lấy lại dữ liệu.
+// Dừng ở token đầu tiên không thuộc các ứng viên của mô hình.
+func (g *NgramGenerator) Recover(text string) ([]byte, error) {
+	m := g.Model
+	coder := newArithmeticEncoder()
+	history := make([]uint32, m.Order-1)
+	for _, token := range splitNgramTokens(text) {
+		id, ok := m.ids[strings.ToLower(token)]
+		if !ok {
+			break
+		}
+		candidates := m.candidates(history)
+		symbol := -1
+		for i, candidate := range candidates {
+			if candidate.token == id {
+				symbol = i
+				break
+			}
+		}
+		if symbol < 0 {
+			break
+		}
+		cum := ngramFrequencies(candidates)
+		coder.narrow(cum[symbol], cum[symbol+1], cum[len(cum)-1])
+		if len(history) > 0 {
+			history = append(history[1:], id)
+		}
+	}
+
+	whitened := bitutil.BitsToBytes(coder.out)
+	if len(whitened) <= ngramNonceSize {
+		return nil, ErrNoHiddenData
+	}
+	nonce, body := whitened[:ngramNonceSize], whitened[ngramNonceSize:]
+	stream := g.keystream(nonce, len(body))
+	data := make([]byte, len(body))
+	for i, b := range body {
+		data[i] = b ^ stream[i]
+	}
+	return data, nil
+}
+
+// keystream - Dòng khóa làm trắng dữ liệu, xác định bởi mô hình và nonce
+func (g *NgramGenerator) keystream(nonce []byte, n int) []byte {
+	stream := make([]byte, 0, n+sha256.Size)
+	var counter [8]byte
+	for i := uint64(0); len(stream) < n; i++ {
+		binary.BigEndian.PutUint64(counter[:], i)
+		h := sha256.New()
+		h.Write(g.Model.fingerprint[:])
+		h.Write(nonce)
+		h.Write(counter[:])
+		stream = h.Sum(stream)
+	}
+	return stream[:n]
+}
@@ -0,0 +1,84 @@
+package stego
+
+import (
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// NgramFields - Mô hình dùng cho request, khi trích xuất phải dùng đúng mô hình đã dùng khi sinh
+type NgramFields struct {
+	// Language - Mã ngôn ngữ của mô hình, mặc định "vi"
+	Language string `json:"language"`
+}
+
+// generator - Tạo bộ sinh văn bản theo mô hình trong request
+func (f *NgramFields) generator(library *NgramLibrary) (*NgramGenerator, error) {
+	language := f.Language
+	if language == "" {
+		language = "vi"
+	}
+	model, err := library.Get(language)
+	if err != nil {
+		return nil, err
+	}
+	return NewNgramGenerator(model), nil
+}
+
+// NgramGenerateRequest - Request body cho sinh văn bản phủ mang message
+type NgramGenerateRequest struct {
+	Message    string      `json:"message" binding:"required"`
+	Passphrase string      `json:"passphrase"`
+	ECC        *ECCRequest `json:"ecc"`
+	NgramFields
+}
+
+// NgramExtractRequest - Request body cho trích xuất tin từ văn bản đã sinh
+type NgramExtractRequest struct {
+	ExtractFields
+	NgramFields
+}
+
+// NgramModels - Danh sách mô hình n-gram có sẵn
+func (h *Handler) NgramModels(c *gin.Context) {
+	utils.RespondWithSuccess(c, http.StatusOK, "N-gram models retrieved successfully", h.service.Ngrams().Models())
+}
+
+// NgramGenerate - Sinh văn bản phủ mang message bằng mô hình n-gram
+func (h *Handler) NgramGenerate(c *gin.Context) {
+	var req NgramGenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	generator, err := req.generator(h.service.Ngrams())
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := h.service.Generate(generator, req.Message, EmbedOptions{
+		Method:     MethodNgram,
+		Passphrase: req.Passphrase,
+		ECC:        req.ECC.options(),
+	})
+	respondEmbedded(c, text, err)
+}
+
+// NgramExtract - Trích xuất message từ văn bản đã sinh bằng mô hình n-gram
+func (h *Handler) NgramExtract(c *gin.Context) {
+	var req NgramExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	generator, err := req.generator(h.service.Ngrams())
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	result, err := h.service.ExtractGenerated(generator, req.Text, req.options(MethodNgram))
+	respondExtracted(c, result, err)
+}
@@ -0,0 +1,174 @@
+package stego
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tên và ID của phương pháp dạng chuẩn hóa Unicode
+const (
+	MethodNormalization   = "normalization"
+	methodIDNormalization = 6
+)
+
+// Các dạng chuẩn hóa của văn bản
+const (
+	FormNFC   = "NFC"
+	FormNFD   = "NFD"
+	FormMixed = "mixed"
+	FormNone  = "none"
+)
+
+// Normalization - Giấu dữ liệu bằng dạng lưu của các chữ có dấu: dựng sẵn (NFC) là 0,
+// tổ hợp (NFD) là 1. Hai dạng hiển thị giống hệt nhau nên mỗi chữ có dấu mang 1 bit,
+// rất hợp với văn bản tiếng Việt. Dữ liệu mất khi văn bản bị chuẩn hóa.
+type Normalization struct{}
+
+// NewNormalization - Tạo phương pháp dạng chuẩn hóa
+func NewNormalization() *Normalization {
+	return &Normalization{}
+}
+
+// ID - ID phương pháp trong header container
+func (n *Normalization) ID() uint8 {
+	return methodIDNormalization
+}
+
+// Name - Tên phương pháp
+func (n *Normalization) Name() string {
+	return MethodNormalization
+}
+
+// Capabilities - Đặc tính của phương pháp dạng chuẩn hóa
+func (n *Normalization) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: false,
+		BitsPerChar:           1,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu, bằng số chữ có dấu
+func (n *Normalization) Capacity(cover string) int {
+	return len(accentedSlots(cover))
+}
+
+// Embed - Viết mỗi chữ có dấu ở dạng dựng sẵn hoặc tổ hợp theo từng bit của data
+func (n *Normalization) Embed(cover string, data []byte) (string, error) {
+	if cover == "" {
+		return "", ErrEmptyCover
+	}
+	slots := accentedSlots(cover)
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > len(slots) {
+		return "", ErrCoverTooSmall
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(cover) + len(bits))
+	pos := 0
+	for i, bit := range bits {
+		slot := slots[i]
+		sb.WriteString(cover[pos:slot.start])
+		segment := cover[slot.start:slot.end]
+		if bit == 1 {
+			sb.WriteString(norm.NFD.String(segment))
+		} else {
+			sb.WriteString(norm.NFC.String(segment))
+		}
+		pos = slot.end
+	}
+	sb.WriteString(cover[pos:])
+	return sb.String(), nil
+}
+
+// Extract - Đọc dạng lưu của từng chữ có dấu
+func (n *Normalization) Extract(text string) ([]byte, error) {
+	slots := accentedSlots(text)
+	bits := make([]byte, len(slots))
+	for i, slot := range slots {
+		if slot.decomposed {
+			bits[i] = 1
+		}
+	}
+	return bitutil.BitsToBytes(bits), nil
+}
+
+// accentedSlot - Một chữ Latin có dấu (kèm các dấu kết hợp theo sau) và dạng lưu hiện tại
+type accentedSlot struct {
+	start, end int
+	decomposed bool
+}
+
+// accentedSlots - Tìm các chữ Latin có dấu có cả dạng dựng sẵn lẫn dạng tổ hợp.
+// Chữ không có dạng dựng sẵn (ví dụ chữ kèm dấu lạ) hoặc không có dấu như "đ" bị bỏ qua.
+func accentedSlots(text string) []accentedSlot {
+	var slots []accentedSlot
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		end := i + size
+		for end < len(text) {
+			mark, n := utf8.DecodeRuneInString(text[end:])
+			if !unicode.Is(unicode.Mn, mark) {
+				break
+			}
+			end += n
+		}
+		segment := text[i:end]
+		i = end
+
+		if !unicode.Is(unicode.Latin, r) {
+			continue
+		}
+		composed := norm.NFC.String(segment)
+		if utf8.RuneCountInString(composed) != 1 || composed == norm.NFD.String(segment) {
+			continue
+		}
+		slots = append(slots, accentedSlot{
+			start:      i - len(segment),
+			end:        end,
+			decomposed: segment != composed,
+		})
+	}
+	return slots
+}
+
+// NormalizationReport - Kết quả phân tích dạng chuẩn hóa của văn bản
+type NormalizationReport struct {
+	AccentedCharacters int    `json:"accented_characters"`
+	Composed           int    `json:"composed"`
+	Decomposed         int    `json:"decomposed"`
+	CapacityBits       int    `json:"capacity_bits"`
+	Form               string `json:"form"`
+}
+
+// AnalyzeNormalization - Đếm các chữ có dấu theo dạng lưu và xác định dạng của văn bản
+func AnalyzeNormalization(text string) NormalizationReport {
+	slots := accentedSlots(text)
+	report := NormalizationReport{
+		AccentedCharacters: len(slots),
+		CapacityBits:       len(slots),
+	}
+	for _, slot := range slots {
+		if slot.decomposed {
+			report.Decomposed++
+		} else {
+			report.Composed++
+		}
+	}
+	switch {
+	case len(slots) == 0:
+		report.Form = FormNone
+	case report.Decomposed == 0:
+		report.Form = FormNFC
+	case report.Composed == 0:
+		report.Form = FormNFD
+	default:
+		report.Form = FormMixed
+	}
+	return report
+}
@@ -0,0 +1,107 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// NormalizationEmbedRequest - Request body cho giấu tin bằng dạng chuẩn hóa
+type NormalizationEmbedRequest struct {
+	EmbedFields
+	// Platform - Nền tảng sẽ nhận văn bản, dùng để cảnh báo nếu văn bản bị chuẩn hóa
+	Platform string `json:"platform"`
+}
+
+// NormalizationExtractRequest - Request body cho trích xuất tin từ dạng chuẩn hóa
+type NormalizationExtractRequest struct {
+	ExtractFields
+	Platform string `json:"platform"`
+}
+
+// NormalizationAnalyzeRequest - Request body cho phân tích dạng chuẩn hóa của văn bản
+type NormalizationAnalyzeRequest struct {
+	Text     string `json:"text" binding:"required"`
+	Platform string `json:"platform"`
+}
+
+// NormalizationAnalyzeResponse - Kết quả phân tích kèm cảnh báo về nền tảng đích
+type NormalizationAnalyzeResponse struct {
+	NormalizationReport
+	Platform *PlatformInfo `json:"platform,omitempty"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+// NormalizationAnalyze - Phân tích các chữ có dấu của văn bản và khả năng giữ dữ liệu trên nền tảng đích
+func (h *Handler) NormalizationAnalyze(c *gin.Context) {
+	var req NormalizationAnalyzeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	resp := NormalizationAnalyzeResponse{NormalizationReport: AnalyzeNormalization(req.Text)}
+	if req.Platform != "" {
+		if platform, ok := h.service.Platform(req.Platform); ok {
+			resp.Platform = &platform
+		} else {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("platform %q is unknown, its normalization behaviour cannot be checked", req.Platform))
+		}
+	}
+	if warning := h.normalizationWarning(req.Platform); warning != "" {
+		resp.Warnings = append(resp.Warnings, warning)
+	}
+	if resp.Form == FormNone {
+		resp.Warnings = append(resp.Warnings, "text has no accented characters to carry data")
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, "Text analyzed successfully", resp)
+}
+
+// NormalizationEmbed - Giấu message bằng dạng lưu của các chữ có dấu
+func (h *Handler) NormalizationEmbed(c *gin.Context) {
+	var req NormalizationEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	text, err := h.service.Embed(req.Cover, req.Message, req.options(MethodNormalization))
+	var warnings []string
+	if warning := h.normalizationWarning(req.Platform); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	respondEmbedded(c, text, err, warnings...)
+}
+
+// NormalizationExtract - Trích xuất message từ dạng lưu của các chữ có dấu
+func (h *Handler) NormalizationExtract(c *gin.Context) {
+	var req NormalizationExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	result, err := h.service.Extract(req.Text, req.options(MethodNormalization))
+	if errors.Is(err, ErrNoHiddenData) {
+		if warning := h.normalizationWarning(req.Platform); warning != "" {
+			err = fmt.Errorf("%w (%s)", err, warning)
+		}
+	}
+	respondExtracted(c, result, err)
+}
+
+// normalizationWarning - Cảnh báo nếu nền tảng được biết là chuẩn hóa văn bản
+func (h *Handler) normalizationWarning(name string) string {
+	if name == "" {
+		return ""
+	}
+	platform, ok := h.service.Platform(name)
+	if !ok || platform.Normalization == "" {
+		return ""
+	}
+	return fmt.Sprintf("platform %q normalizes text to %s, the hidden data will not survive", name, platform.Normalization)
+}
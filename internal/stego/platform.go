@@ -0,0 +1,85 @@
+package stego
+
+import (
+	"sort"
+	"strings"
+)
+
+// PlatformInfo - Hành vi đã biết của một nền tảng nhận văn bản đã giấu tin
+type PlatformInfo struct {
+	Name string `json:"name"`
+	// StripsVariationSelectors - Nền tảng xóa các ký tự variation selector (U+FE00–FE0F, U+E0100–E01EF)
+	StripsVariationSelectors bool `json:"strips_variation_selectors"`
+	// Normalization - Dạng chuẩn hóa (NFC/NFD) nền tảng áp dụng cho văn bản, rỗng nếu giữ nguyên
+	Normalization string `json:"normalization,omitempty"`
+	// Notes - Căn cứ của hành vi đã ghi
+	Notes string `json:"notes,omitempty"`
+}
+
+// defaultPlatforms - Các nền tảng có hành vi được tài liệu hóa; có thể bổ sung qua
+// Config.Platforms. Bảng chỉ ghi các biến đổi đã biết chắc: nền tảng không có trong bảng là
+// chưa rõ hành vi, không phải là an toàn.
+var defaultPlatforms = []PlatformInfo{
+	{
+		Name:                     "sms",
+		StripsVariationSelectors: true,
+		Notes:                    "gateways that fall back to the GSM-7 alphabet (3GPP TS 23.038) drop characters outside it, and UCS-2 cannot carry the supplementary selectors U+E0100–E01EF",
+	},
+	{
+		Name:          "macos-hfs-filenames",
+		Normalization: FormNFD,
+		Notes:         "HFS+ stores file names in a variant of NFD (Apple TN1150); APFS keeps the form it is given",
+	},
+	{
+		Name:                     "domain-names",
+		StripsVariationSelectors: true,
+		Normalization:            FormNFC,
+		Notes:                    "IDNA processing (UTS #46) maps variation selectors to nothing and normalizes labels to NFC",
+	},
+}
+
+// platformTable - Bảng nền tảng tra cứu theo tên (không phân biệt hoa thường)
+type platformTable map[string]PlatformInfo
+
+// newPlatformTable - Gộp bảng có sẵn với các nền tảng trong cấu hình.
+// Hành vi của cùng một nền tảng được cộng dồn qua các mục.
+func newPlatformTable(overrides []PlatformInfo) platformTable {
+	table := make(platformTable)
+	for _, list := range [][]PlatformInfo{defaultPlatforms, overrides} {
+		for _, platform := range list {
+			platform.Name = strings.ToLower(strings.TrimSpace(platform.Name))
+			if platform.Name == "" {
+				continue
+			}
+			if existing, ok := table[platform.Name]; ok {
+				platform.StripsVariationSelectors = platform.StripsVariationSelectors || existing.StripsVariationSelectors
+				if platform.Normalization == "" {
+					platform.Normalization = existing.Normalization
+				}
+				if platform.Notes == "" {
+					platform.Notes = existing.Notes
+				}
+			}
+			table[platform.Name] = platform
+		}
+	}
+	return table
+}
+
+// lookup - Tìm nền tảng theo tên
+func (t platformTable) lookup(name string) (PlatformInfo, bool) {
+	platform, ok := t[strings.ToLower(strings.TrimSpace(name))]
+	return platform, ok
+}
+
+// list - Danh sách nền tảng theo tên
+func (t platformTable) list() []PlatformInfo {
+	platforms := make([]PlatformInfo, 0, len(t))
+	for _, platform := range t {
+		platforms = append(platforms, platform)
+	}
+	sort.Slice(platforms, func(i, j int) bool {
+		return platforms[i].Name < platforms[j].Name
+	})
+	return platforms
+}
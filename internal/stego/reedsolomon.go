@@ -0,0 +1,163 @@
+package stego
+
+import "errors"
+
+// ErrTooManyErrors - Số lỗi vượt quá khả năng sửa của mã Reed-Solomon
+var ErrTooManyErrors = errors.New("too many corrupted symbols to repair")
+
+// rsGeneratorPoly - Đa thức sinh (x - α^0)(x - α^1)...(x - α^(nsym-1))
+func rsGeneratorPoly(nsym int) []byte {
+	g := []byte{1}
+	for i := 0; i < nsym; i++ {
+		g = gfPolyMul(g, []byte{1, gfPow(2, i)})
+	}
+	return g
+}
+
+// rsEncode - Mã hóa hệ thống: trả về msg kèm nsym byte parity phía sau
+func rsEncode(msg []byte, nsym int) []byte {
+	if len(msg)+nsym > 255 {
+		panic("reed-solomon: codeword longer than 255 symbols")
+	}
+	gen := rsGeneratorPoly(nsym)
+	padded := make([]byte, len(msg)+nsym)
+	copy(padded, msg)
+	_, remainder := gfPolyDiv(padded, gen)
+	out := make([]byte, len(msg)+nsym)
+	copy(out, msg)
+	copy(out[len(msg):], remainder)
+	return out
+}
+
+// rsSyndromes - Tính syndrome, phần tử đầu luôn bằng 0 để đơn giản hóa chỉ số
+func rsSyndromes(codeword []byte, nsym int) []byte {
+	synd := make([]byte, nsym+1)
+	for i := 0; i < nsym; i++ {
+		synd[i+1] = gfPolyEval(codeword, gfPow(2, i))
+	}
+	return synd
+}
+
+func allZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// rsErrorLocator - Tìm đa thức định vị lỗi bằng thuật toán Berlekamp-Massey
+func rsErrorLocator(synd []byte, nsym int) ([]byte, error) {
+	errLoc := []byte{1}
+	oldLoc := []byte{1}
+	shift := len(synd) - nsym
+	for i := 0; i < nsym; i++ {
+		k := i + shift
+		delta := synd[k]
+		for j := 1; j < len(errLoc); j++ {
+			delta ^= gfMul(errLoc[len(errLoc)-1-j], synd[k-j])
+		}
+		oldLoc = append(oldLoc, 0)
+		if delta != 0 {
+			if len(oldLoc) > len(errLoc) {
+				newLoc := gfPolyScale(oldLoc, delta)
+				oldLoc = gfPolyScale(errLoc, gfInverse(delta))
+				errLoc = newLoc
+			}
+			errLoc = gfPolyAdd(errLoc, gfPolyScale(oldLoc, delta))
+		}
+	}
+	for len(errLoc) > 0 && errLoc[0] == 0 {
+		errLoc = errLoc[1:]
+	}
+	if (len(errLoc)-1)*2 > nsym {
+		return nil, ErrTooManyErrors
+	}
+	return errLoc, nil
+}
+
+// rsFindErrors - Tìm vị trí lỗi bằng Chien search
+func rsFindErrors(errLocReversed []byte, n int) ([]int, error) {
+	errs := len(errLocReversed) - 1
+	var positions []int
+	for i := 0; i < n; i++ {
+		if gfPolyEval(errLocReversed, gfPow(2, i)) == 0 {
+			positions = append(positions, n-1-i)
+		}
+	}
+	if len(positions) != errs {
+		return nil, ErrTooManyErrors
+	}
+	return positions, nil
+}
+
+// rsCorrectErrata - Tính độ lớn lỗi bằng thuật toán Forney và sửa codeword
+func rsCorrectErrata(codeword, synd []byte, positions []int) []byte {
+	coefPos := make([]int, len(positions))
+	for i, p := range positions {
+		coefPos[i] = len(codeword) - 1 - p
+	}
+
+	errLoc := []byte{1}
+	for _, p := range coefPos {
+		errLoc = gfPolyMul(errLoc, gfPolyAdd([]byte{1}, []byte{gfPow(2, p), 0}))
+	}
+
+	reversedSynd := make([]byte, len(synd))
+	for i := range synd {
+		reversedSynd[i] = synd[len(synd)-1-i]
+	}
+	divisor := make([]byte, len(errLoc)+1)
+	divisor[0] = 1
+	_, errEval := gfPolyDiv(gfPolyMul(reversedSynd, errLoc), divisor)
+
+	x := make([]byte, len(coefPos))
+	for i, p := range coefPos {
+		x[i] = gfPow(2, p)
+	}
+
+	magnitudes := make([]byte, len(codeword))
+	for i, xi := range x {
+		xiInv := gfInverse(xi)
+		var locPrime byte = 1
+		for j := range x {
+			if j != i {
+				locPrime = gfMul(locPrime, 1^gfMul(xiInv, x[j]))
+			}
+		}
+		y := gfMul(xi, gfPolyEval(errEval, xiInv))
+		magnitudes[positions[i]] = gfDiv(y, locPrime)
+	}
+	return gfPolyAdd(codeword, magnitudes)
+}
+
+// rsDecode - Sửa lỗi codeword, trả về phần dữ liệu và số symbol đã được sửa
+func rsDecode(codeword []byte, nsym int) ([]byte, int, error) {
+	if len(codeword) <= nsym {
+		return nil, 0, ErrTruncated
+	}
+	synd := rsSyndromes(codeword, nsym)
+	if allZero(synd) {
+		return codeword[:len(codeword)-nsym], 0, nil
+	}
+
+	errLoc, err := rsErrorLocator(synd, nsym)
+	if err != nil {
+		return nil, 0, err
+	}
+	reversed := make([]byte, len(errLoc))
+	for i := range errLoc {
+		reversed[i] = errLoc[len(errLoc)-1-i]
+	}
+	positions, err := rsFindErrors(reversed, len(codeword))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	corrected := rsCorrectErrata(codeword, synd, positions)
+	if !allZero(rsSyndromes(corrected, nsym)) {
+		return nil, 0, ErrTooManyErrors
+	}
+	return corrected[:len(corrected)-nsym], len(positions), nil
+}
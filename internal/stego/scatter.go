@@ -0,0 +1,90 @@
+package stego
+
+import (
+	"crypto/rand"
+	mathrand "math/rand/v2"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+)
+
+// Scattered - Lớp rải bit theo khóa, bọc quanh một phương pháp bất kỳ.
+// Bit thứ i của dữ liệu được ghi vào vị trí mang tin perm[i], với perm là hoán vị của
+// toàn bộ dung lượng (làm tròn xuống theo byte) sinh từ khóa; các vị trí còn lại được điền
+// bit ngẫu nhiên. Vì vậy mọi vị trí mang tin đều bị dùng: với các phương pháp chèn ký tự
+// (zero-width, variation selector) văn bản sẽ chứa đủ toàn bộ dung lượng.
+// Trích xuất không có khóa hoặc sai khóa cho ra dữ liệu ngẫu nhiên và không tìm thấy container.
+type Scattered struct {
+	Method
+	seed [32]byte
+}
+
+// NewScattered - Bọc phương pháp với khóa rải bit (passphrase hoặc stego key riêng)
+func NewScattered(m Method, key string) *Scattered {
+	return newScatteredWithSeed(m, scatterSeed(key))
+}
+
+// newScatteredWithSeed - Bọc phương pháp với seed đã dẫn xuất, dùng khi cùng một khóa
+// được áp dụng cho nhiều phương pháp để không phải chạy lại Argon2id
+func newScatteredWithSeed(m Method, seed [32]byte) *Scattered {
+	return &Scattered{Method: m, seed: seed}
+}
+
+// scatterSeed - Seed của hoán vị dẫn xuất từ khóa rải bit
+func scatterSeed(key string) [32]byte {
+	var seed [32]byte
+	copy(seed[:], derivePositionKey(key, "scatter"))
+	return seed
+}
+
+// Capacity - Dung lượng của phương pháp gốc làm tròn xuống theo byte
+func (s *Scattered) Capacity(cover string) int {
+	return s.Method.Capacity(cover) / 8 * 8
+}
+
+// Embed - Rải các bit của data lên toàn bộ vị trí mang tin rồi giấu bằng phương pháp gốc
+func (s *Scattered) Embed(cover string, data []byte) (string, error) {
+	n := s.Capacity(cover)
+	if len(data)*8 > n {
+		return "", ErrCoverTooSmall
+	}
+	fill := make([]byte, n/8)
+	if _, err := rand.Read(fill); err != nil {
+		return "", err
+	}
+	bits := bitutil.BytesToBits(fill)
+	perm := s.permutation(n)
+	for i, bit := range bitutil.BytesToBits(data) {
+		bits[perm[i]] = bit
+	}
+	return s.Method.Embed(cover, bitutil.BitsToBytes(bits))
+}
+
+// Extract - Đọc toàn bộ vị trí mang tin rồi đảo hoán vị để lấy lại thứ tự bit
+func (s *Scattered) Extract(text string) ([]byte, error) {
+	raw, err := s.Method.Extract(text)
+	if err != nil {
+		return nil, err
+	}
+	bits := bitutil.BytesToBits(raw)
+	perm := s.permutation(len(bits))
+	data := make([]byte, len(bits))
+	for i := range data {
+		data[i] = bits[perm[i]]
+	}
+	return bitutil.BitsToBytes(data), nil
+}
+
+// permutation - Hoán vị Fisher-Yates của n vị trí, sinh từ ChaCha8 với seed là khóa.
+// Tự cài đặt thay cho rand.Perm để kết quả không phụ thuộc phiên bản Go.
+func (s *Scattered) permutation(n int) []int {
+	rng := mathrand.NewChaCha8(s.seed)
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := int(rng.Uint64() % uint64(i+1))
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm
+}
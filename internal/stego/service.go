@@ -0,0 +1,416 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMethod - Phương pháp được dùng khi request không chỉ định
+const DefaultMethod = MethodZeroWidth
+
+// ErrNoHiddenData - Không tìm thấy dữ liệu giấu trong văn bản
+var ErrNoHiddenData = errors.New("no hidden data found")
+
+// Config - Cấu hình cho stego service
+type Config struct {
+	KDF       KDFConfig
+	Homoglyph HomoglyphConfig
+	// Platforms - Bổ sung hoặc ghi đè bảng nền tảng có sẵn
+	Platforms []PlatformInfo
+	// Synonyms - Các từ điển đồng nghĩa, nil để dùng từ điển đi kèm
+	Synonyms *SynonymLibrary
+	// Ngrams - Các mô hình n-gram để sinh văn bản, nil để dùng mô hình đi kèm
+	Ngrams *NgramLibrary
+}
+
+// HomoglyphConfig - Bảng ký tự đồng dạng dùng cho phương pháp homoglyph đã đăng ký
+type HomoglyphConfig struct {
+	// Table - Phiên bản bảng, để trống để dùng DefaultHomoglyphTable
+	Table string
+	// Exclude - Các ký tự hiển thị khác biệt trong font của client, không dùng làm vật mang
+	Exclude string
+}
+
+// MethodInfo - Thông tin một phương pháp giấu tin
+type MethodInfo struct {
+	ID           uint8        `json:"id"`
+	Name         string       `json:"name"`
+	Capabilities Capabilities `json:"capabilities"`
+}
+
+// EmbedOptions - Tùy chọn khi giấu tin
+type EmbedOptions struct {
+	Method     string
+	Passphrase string
+	// ECC - Bật mã sửa lỗi Reed-Solomon nếu khác nil
+	ECC *ECCOptions
+	// Deterministic - Salt cố định theo phương pháp và nonce dẫn xuất từ khóa và message
+	// thay vì ngẫu nhiên
+	Deterministic bool
+	// ScatterKey - Rải bit lên các vị trí mang tin theo khóa này nếu khác rỗng
+	ScatterKey string
+}
+
+// ExtractOptions - Tùy chọn khi trích xuất
+type ExtractOptions struct {
+	// Method - Để trống để tự nhận diện phương pháp
+	Method     string
+	Passphrase string
+	// ScatterKey - Khóa đã dùng để rải bit khi giấu tin
+	ScatterKey string
+}
+
+// ExtractResult - Kết quả trích xuất
+type ExtractResult struct {
+	Method    string
+	Version   uint8
+	Encrypted bool
+	// Compressed - Payload đã được nén trước khi giấu
+	Compressed bool
+	// Corrected - Số byte bị hỏng đã được ECC sửa
+	Corrected int
+	Message   string
+	// File - File đã giấu, nil nếu dữ liệu là message
+	File *HiddenFile
+	// Split - Thông tin bộ mảnh nếu dữ liệu được ghép từ nhiều văn bản
+	Split *SplitInfo
+}
+
+// Service - Interface cho stego service
+type Service interface {
+	Methods() []MethodInfo
+	Embed(cover, message string, opts EmbedOptions) (string, error)
+	Extract(text string, opts ExtractOptions) (*ExtractResult, error)
+	EmbedWithMethod(m Method, cover, message string, opts EmbedOptions) (string, error)
+	ExtractWithMethod(m Method, text string, opts ExtractOptions) (*ExtractResult, error)
+	Capacity(cover string, opts CapacityOptions) (*CapacityReport, error)
+	Platforms() []PlatformInfo
+	Platform(name string) (PlatformInfo, bool)
+	Synonyms() *SynonymLibrary
+	Generate(g Generator, message string, opts EmbedOptions) (string, error)
+	ExtractGenerated(g Generator, text string, opts ExtractOptions) (*ExtractResult, error)
+	Ngrams() *NgramLibrary
+	GenerateCover(opts CoverOptions) (*GeneratedCover, error)
+	EmbedFile(cover string, file *HiddenFile, opts EmbedOptions) (string, error)
+	Split(covers []string, message string, threshold int, opts EmbedOptions) (*SplitResult, error)
+	Combine(texts []string, opts ExtractOptions) (*ExtractResult, error)
+}
+
+// StegoService - Triển khai Service interface
+type StegoService struct {
+	registry  Registry
+	config    Config
+	platforms platformTable
+}
+
+// NewStegoService - Tạo service mới
+func NewStegoService(registry Registry, config Config) Service {
+	if config.KDF == (KDFConfig{}) {
+		config.KDF = DefaultKDFConfig
+	}
+	if config.Synonyms == nil {
+		config.Synonyms = BundledSynonyms()
+	}
+	if config.Ngrams == nil {
+		config.Ngrams = BundledNgramModels()
+	}
+	return &StegoService{
+		registry:  registry,
+		config:    config,
+		platforms: newPlatformTable(config.Platforms),
+	}
+}
+
+// Methods - Danh sách các phương pháp đã đăng ký
+func (s *StegoService) Methods() []MethodInfo {
+	methods := s.registry.List()
+	infos := make([]MethodInfo, 0, len(methods))
+	for _, method := range methods {
+		infos = append(infos, MethodInfo{
+			ID:           method.ID(),
+			Name:         method.Name(),
+			Capabilities: method.Capabilities(),
+		})
+	}
+	return infos
+}
+
+// Embed - Giấu message vào văn bản phủ bằng phương pháp đã đăng ký
+func (s *StegoService) Embed(cover, message string, opts EmbedOptions) (string, error) {
+	m, err := s.getMethod(opts.Method)
+	if err != nil {
+		return "", err
+	}
+	return s.EmbedWithMethod(m, cover, message, opts)
+}
+
+// EmbedWithMethod - Giấu message bằng một phương pháp được cấu hình riêng cho request
+func (s *StegoService) EmbedWithMethod(m Method, cover, message string, opts EmbedOptions) (string, error) {
+	return s.embed(m, cover, &Container{Payload: []byte(message)}, opts)
+}
+
+// EmbedFile - Giấu file kèm tên, kích thước và kiểu MIME vào văn bản phủ
+func (s *StegoService) EmbedFile(cover string, file *HiddenFile, opts EmbedOptions) (string, error) {
+	m, err := s.getMethod(opts.Method)
+	if err != nil {
+		return "", err
+	}
+	return s.embed(m, cover, &Container{Flags: FlagFile, Payload: marshalHiddenFile(file)}, opts)
+}
+
+// embed - Đóng gói container (đã có payload và các cờ của payload) và giấu bằng phương pháp m
+func (s *StegoService) embed(m Method, cover string, container *Container, opts EmbedOptions) (string, error) {
+	if strings.TrimSpace(cover) == "" {
+		return "", ErrEmptyCover
+	}
+	if opts.ScatterKey != "" {
+		m = NewScattered(m, opts.ScatterKey)
+	}
+
+	container.MethodID = m.ID()
+	data, err := s.pack(container, opts)
+	if err != nil {
+		return "", err
+	}
+	if len(data)*8 > m.Capacity(cover) {
+		return "", fmt.Errorf("%w: need %d bits, cover holds %d", ErrCoverTooSmall, len(data)*8, m.Capacity(cover))
+	}
+	return m.Embed(cover, data)
+}
+
+// pack - Áp dụng các lớp xử lý lên payload của container theo các tùy chọn rồi ghi ra byte
+func (s *StegoService) pack(container *Container, opts EmbedOptions) ([]byte, error) {
+	// Flags phải được đặt trước khi mã hóa vì chúng nằm trong dữ liệu được xác thực.
+	// Chỉ nén khi bản nén nhỏ hơn; nén phải làm trước khi mã hóa vì ciphertext không nén được.
+	// Mảnh bí mật là dữ liệu ngẫu nhiên, bí mật đã được nén trước khi chia.
+	if !container.Flags.Has(FlagShare) {
+		if compressed, ok := compressPayload(container.Payload); ok {
+			container.Flags |= FlagCompressed
+			container.Payload = compressed
+		}
+	}
+	if opts.ECC != nil {
+		container.Flags |= FlagECC
+	}
+	if opts.Passphrase != "" {
+		params, err := newKDFParams(s.config.KDF)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Deterministic {
+			params.Salt = deterministicSalt(container.MethodID)
+		}
+		container.Flags |= FlagEncrypted
+		container.KDF = params
+		container.Payload, err = encryptPayload(opts.Passphrase, params, container.Payload, container.associatedData(), opts.Deterministic)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.ECC != nil {
+		params, err := newECCParams(*opts.ECC, len(container.Payload))
+		if err != nil {
+			return nil, err
+		}
+		container.ECC = params
+	}
+	return container.MarshalBinary()
+}
+
+// Extract - Trích xuất message đã giấu trong văn bản.
+// Nếu không chỉ định phương pháp, thử lần lượt các phương pháp đã đăng ký.
+func (s *StegoService) Extract(text string, opts ExtractOptions) (*ExtractResult, error) {
+	candidates, err := s.candidates(opts.Method)
+	if err != nil {
+		return nil, err
+	}
+	return s.extract(candidates, text, opts)
+}
+
+// candidates - Các phương pháp cần thử khi trích xuất: phương pháp đã chỉ định hoặc
+// tất cả phương pháp đã đăng ký
+func (s *StegoService) candidates(method string) ([]Method, error) {
+	if method == "" {
+		return s.registry.List(), nil
+	}
+	m, err := s.registry.Get(method)
+	if err != nil {
+		return nil, err
+	}
+	return []Method{m}, nil
+}
+
+// ExtractWithMethod - Trích xuất bằng một phương pháp được cấu hình riêng cho request
+func (s *StegoService) ExtractWithMethod(m Method, text string, opts ExtractOptions) (*ExtractResult, error) {
+	return s.extract([]Method{m}, text, opts)
+}
+
+// extract - Thử trích xuất lần lượt bằng các phương pháp ứng viên
+func (s *StegoService) extract(candidates []Method, text string, opts ExtractOptions) (*ExtractResult, error) {
+	m, container, err := s.find(scatterCandidates(candidates, opts.ScatterKey), text)
+	if err != nil {
+		return nil, err
+	}
+	return s.unpack(m.Name(), container, opts)
+}
+
+// scatterCandidates - Bọc các phương pháp ứng viên bằng lớp rải bit nếu có khóa.
+// Khóa được dẫn xuất một lần cho tất cả ứng viên vì mỗi lần dẫn xuất là một lần Argon2id.
+func scatterCandidates(candidates []Method, key string) []Method {
+	if key == "" {
+		return candidates
+	}
+	seed := scatterSeed(key)
+	scattered := make([]Method, len(candidates))
+	for i, m := range candidates {
+		scattered[i] = newScatteredWithSeed(m, seed)
+	}
+	return scattered
+}
+
+// find - Tìm container bằng phương pháp ứng viên đầu tiên đọc được
+func (s *StegoService) find(candidates []Method, text string) (Method, *Container, error) {
+	// Lỗi cụ thể nhất gặp được (ví dụ dữ liệu bị cắt cụt) được ưu tiên trả về
+	// thay cho lỗi không tìm thấy dữ liệu
+	lastErr := ErrNoHiddenData
+	for _, m := range candidates {
+		container, err := s.open(m, text)
+		if err != nil {
+			if !errors.Is(err, ErrNoHiddenData) {
+				lastErr = err
+			}
+			continue
+		}
+		if container.MethodID != m.ID() {
+			continue
+		}
+		return m, container, nil
+	}
+	return nil, nil, lastErr
+}
+
+// open - Đọc container từ văn bản bằng một phương pháp
+func (s *StegoService) open(m Method, text string) (*Container, error) {
+	data, err := m.Extract(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.Name(), err)
+	}
+	return ParseContainer(data)
+}
+
+// unpack - Giải các lớp xử lý của payload theo flags trong header
+func (s *StegoService) unpack(method string, container *Container, opts ExtractOptions) (*ExtractResult, error) {
+	if container.Flags.Has(FlagShare) {
+		return nil, ErrSecretShare
+	}
+	payload, err := s.decrypt(container, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExtractResult{
+		Method:    method,
+		Version:   container.Version,
+		Encrypted: container.Flags.Has(FlagEncrypted),
+		Corrected: container.Corrected,
+	}
+	if err := decodePayload(result, container.Flags, payload); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decrypt - Giải mã payload nếu container được mã hóa
+func (s *StegoService) decrypt(container *Container, opts ExtractOptions) ([]byte, error) {
+	if !container.Flags.Has(FlagEncrypted) {
+		return container.Payload, nil
+	}
+	if opts.Passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	return decryptPayload(opts.Passphrase, container.KDF, container.Payload, container.associatedData())
+}
+
+// decodePayload - Giải nén và đọc message hoặc file từ payload đã giải mã vào result
+func decodePayload(result *ExtractResult, flags Flags, payload []byte) error {
+	if flags.Has(FlagCompressed) {
+		var err error
+		payload, err = decompressPayload(payload)
+		if err != nil {
+			return err
+		}
+		result.Compressed = true
+	}
+
+	if flags.Has(FlagFile) {
+		file, err := unmarshalHiddenFile(payload)
+		if err != nil {
+			return err
+		}
+		result.File = file
+		return nil
+	}
+
+	if !utf8.Valid(payload) {
+		return errors.New("hidden data is not valid UTF-8 text")
+	}
+	result.Message = string(payload)
+	return nil
+}
+
+// Generate - Sinh văn bản phủ mang message. Salt cố định theo bộ sinh và nonce dẫn xuất từ
+// khóa và message nên cùng bộ sinh, passphrase và message luôn cho cùng văn bản.
+func (s *StegoService) Generate(g Generator, message string, opts EmbedOptions) (string, error) {
+	opts.Deterministic = true
+	data, err := s.pack(&Container{MethodID: g.ID(), Payload: []byte(message)}, opts)
+	if err != nil {
+		return "", err
+	}
+	return g.Generate(data)
+}
+
+// ExtractGenerated - Trích xuất message từ văn bản đã sinh bằng cùng bộ sinh
+func (s *StegoService) ExtractGenerated(g Generator, text string, opts ExtractOptions) (*ExtractResult, error) {
+	data, err := g.Recover(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", g.Name(), err)
+	}
+	container, err := ParseContainer(data)
+	if err != nil {
+		return nil, err
+	}
+	if container.MethodID != g.ID() {
+		return nil, ErrNoHiddenData
+	}
+	return s.unpack(g.Name(), container, opts)
+}
+
+// Platforms - Danh sách nền tảng đã biết
+func (s *StegoService) Platforms() []PlatformInfo {
+	return s.platforms.list()
+}
+
+// Platform - Tra cứu hành vi của một nền tảng theo tên
+func (s *StegoService) Platform(name string) (PlatformInfo, bool) {
+	return s.platforms.lookup(name)
+}
+
+// Synonyms - Thư viện từ điển đồng nghĩa
+func (s *StegoService) Synonyms() *SynonymLibrary {
+	return s.config.Synonyms
+}
+
+// Ngrams - Thư viện mô hình n-gram
+func (s *StegoService) Ngrams() *NgramLibrary {
+	return s.config.Ngrams
+}
+
+// getMethod - Lấy phương pháp theo tên, dùng mặc định nếu tên rỗng
+func (s *StegoService) getMethod(name string) (Method, error) {
+	if name == "" {
+		name = DefaultMethod
+	}
+	return s.registry.Get(name)
+}
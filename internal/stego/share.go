@@ -0,0 +1,327 @@
+package stego
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Chia bí mật theo lược đồ Shamir trên GF(256): mỗi byte của bí mật là hệ số tự do của một
+// đa thức bậc threshold-1 với các hệ số còn lại ngẫu nhiên, mảnh thứ i là giá trị của các
+// đa thức tại x = i. Bất kỳ threshold mảnh nào cũng nội suy lại được bí mật, ít hơn thì
+// không lộ thông tin gì.
+const (
+	// shareParamsSize - Kích thước phần mở rộng mảnh bí mật trong header
+	shareParamsSize = 11
+	// shareSetIDSize - Số byte của ID bộ mảnh
+	shareSetIDSize = 8
+	// MaxShares - Số mảnh tối đa (x chạy từ 1 đến 255 trong GF(256))
+	MaxShares = 255
+)
+
+// Các lỗi khi ghép mảnh bí mật
+var (
+	ErrSecretShare      = errors.New("hidden data is one share of a split secret, combine it with the other shares")
+	ErrNotAShare        = errors.New("hidden data is not a share of a split secret")
+	ErrNotEnoughShares  = errors.New("not enough shares to recover the secret")
+	ErrMixedShareSets   = errors.New("carriers belong to different split secrets")
+	ErrInvalidThreshold = errors.New("threshold must be between 2 and the number of covers")
+)
+
+// ShareParams - Tham số mảnh bí mật được lưu trong header container:
+//
+//	set_id    [8]byte  ID ngẫu nhiên chung của các mảnh cùng một bí mật
+//	threshold uint8    số mảnh cần để ghép lại
+//	index     uint8    vị trí x của mảnh (1..total)
+//	total     uint8    tổng số mảnh đã chia
+type ShareParams struct {
+	SetID     [shareSetIDSize]byte
+	Threshold uint8
+	Index     uint8
+	Total     uint8
+}
+
+// SplitInfo - Thông tin bộ mảnh của bí mật đã ghép
+type SplitInfo struct {
+	SetID     string `json:"set_id"`
+	Threshold int    `json:"threshold"`
+	Total     int    `json:"total"`
+	// Indices - Vị trí các mảnh đã dùng để ghép
+	Indices []int `json:"indices"`
+	// Skipped - Các văn bản (đánh số từ 1) bị bỏ qua vì không đọc được mảnh hợp lệ
+	Skipped []int `json:"skipped,omitempty"`
+}
+
+func (p *ShareParams) marshal() []byte {
+	buf := make([]byte, 0, shareParamsSize)
+	buf = append(buf, p.SetID[:]...)
+	return append(buf, p.Threshold, p.Index, p.Total)
+}
+
+func parseShareParams(data []byte) (*ShareParams, error) {
+	p := &ShareParams{
+		Threshold: data[shareSetIDSize],
+		Index:     data[shareSetIDSize+1],
+		Total:     data[shareSetIDSize+2],
+	}
+	copy(p.SetID[:], data[:shareSetIDSize])
+	if p.Threshold < 2 || p.Index == 0 || p.Index > p.Total || p.Threshold > p.Total {
+		return nil, fmt.Errorf("%w: invalid share parameters", ErrCorrupted)
+	}
+	return p, nil
+}
+
+// setID - ID bộ mảnh dạng hex
+func (p *ShareParams) setID() string {
+	return hex.EncodeToString(p.SetID[:])
+}
+
+// splitSecret - Chia secret thành total mảnh, mảnh thứ i (từ 0) ứng với x = i+1
+func splitSecret(secret []byte, threshold, total int) ([][]byte, error) {
+	if threshold < 2 || threshold > total || total > MaxShares {
+		return nil, ErrInvalidThreshold
+	}
+	shares := make([][]byte, total)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret))
+	}
+	// Hệ số bậc cao nhất đứng đầu như các hàm đa thức trong gf256.go
+	poly := make([]byte, threshold)
+	for pos, b := range secret {
+		if _, err := rand.Read(poly[:threshold-1]); err != nil {
+			return nil, err
+		}
+		poly[threshold-1] = b
+		for i := range shares {
+			shares[i][pos] = gfPolyEval(poly, byte(i+1))
+		}
+	}
+	return shares, nil
+}
+
+// combineShares - Nội suy Lagrange tại x = 0 từ các mảnh có vị trí xs.
+// Mọi mảnh phải cùng độ dài và các vị trí phải khác nhau.
+func combineShares(xs []byte, shares [][]byte) []byte {
+	// Hệ số Lagrange chỉ phụ thuộc vào vị trí nên được tính một lần.
+	// Trong GF(2^8) phép trừ là XOR nên (0 - x_m) / (x_j - x_m) = x_m / (x_j ^ x_m).
+	basis := make([]byte, len(xs))
+	for j := range xs {
+		basis[j] = 1
+		for m := range xs {
+			if m != j {
+				basis[j] = gfMul(basis[j], gfDiv(xs[m], xs[j]^xs[m]))
+			}
+		}
+	}
+	secret := make([]byte, len(shares[0]))
+	for pos := range secret {
+		var b byte
+		for j, share := range shares {
+			b ^= gfMul(share[pos], basis[j])
+		}
+		secret[pos] = b
+	}
+	return secret
+}
+
+// SplitResult - Các văn bản đã giấu mảnh, theo thứ tự của văn bản phủ
+type SplitResult struct {
+	SetID     string   `json:"set_id"`
+	Threshold int      `json:"threshold"`
+	Texts     []string `json:"texts"`
+}
+
+// Split - Chia message thành len(covers) mảnh, cần threshold mảnh bất kỳ để ghép lại,
+// và giấu mỗi mảnh vào một văn bản phủ. Mã hóa và ECC được áp dụng riêng cho từng mảnh.
+func (s *StegoService) Split(covers []string, message string, threshold int, opts EmbedOptions) (*SplitResult, error) {
+	m, err := s.getMethod(opts.Method)
+	if err != nil {
+		return nil, err
+	}
+	if threshold < 2 || threshold > len(covers) || len(covers) > MaxShares {
+		return nil, ErrInvalidThreshold
+	}
+
+	secret := []byte(message)
+	flags := FlagShare
+	if compressed, ok := compressPayload(secret); ok {
+		flags |= FlagCompressed
+		secret = compressed
+	}
+	shares, err := splitSecret(secret, threshold, len(covers))
+	if err != nil {
+		return nil, err
+	}
+	params := ShareParams{
+		Threshold: uint8(threshold),
+		Total:     uint8(len(covers)),
+	}
+	if _, err := rand.Read(params.SetID[:]); err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, len(covers))
+	for i, cover := range covers {
+		share := params
+		share.Index = uint8(i + 1)
+		texts[i], err = s.embed(m, cover, &Container{Flags: flags, Share: &share, Payload: shares[i]}, opts)
+		if err != nil {
+			return nil, fmt.Errorf("cover %d: %w", i+1, err)
+		}
+	}
+	return &SplitResult{
+		SetID:     params.setID(),
+		Threshold: threshold,
+		Texts:     texts,
+	}, nil
+}
+
+// Combine - Trích xuất mảnh từ từng văn bản và ghép lại message khi đủ số mảnh.
+// Văn bản không đọc được mảnh, thuộc bộ mảnh khác hoặc mảnh bị hỏng được bỏ qua; chỉ lỗi
+// khi số mảnh hợp lệ còn lại ít hơn threshold. Mảnh trùng vị trí chỉ được tính một lần.
+func (s *StegoService) Combine(texts []string, opts ExtractOptions) (*ExtractResult, error) {
+	candidates, err := s.candidates(opts.Method)
+	if err != nil {
+		return nil, err
+	}
+	candidates = scatterCandidates(candidates, opts.ScatterKey)
+
+	// Lỗi của văn bản cuối cùng bị bỏ qua được trả về nếu không ghép được mảnh nào
+	lastErr := ErrNoHiddenData
+	var skipped []int
+	var reasons []string
+	skip := func(i int, err error) {
+		lastErr = fmt.Errorf("carrier %d: %w", i+1, err)
+		skipped = append(skipped, i+1)
+		reasons = append(reasons, lastErr.Error())
+	}
+
+	carriers := make([]*shareCarrier, 0, len(texts))
+	for i, text := range texts {
+		m, container, err := s.find(candidates, text)
+		if err == nil && !container.Flags.Has(FlagShare) {
+			err = ErrNotAShare
+		}
+		if err != nil {
+			skip(i, err)
+			continue
+		}
+		carriers = append(carriers, &shareCarrier{index: i, method: m, container: container})
+	}
+
+	// Văn bản thuộc bộ mảnh khác được bỏ qua trước để lỗi giải mã của bộ được chọn
+	// là lỗi được trả về khi không ghép được
+	set := largestShareSet(carriers)
+	matched := carriers[:0]
+	for _, carrier := range carriers {
+		if set != shareSetOf(carrier.container) {
+			skip(carrier.index, ErrMixedShareSets)
+			continue
+		}
+		matched = append(matched, carrier)
+	}
+
+	var first *Container
+	var method Method
+	var xs []byte
+	var shares [][]byte
+	corrected := 0
+	for _, carrier := range matched {
+		container := carrier.container
+		if bytes.IndexByte(xs, container.Share.Index) >= 0 {
+			continue
+		}
+		share, err := s.decrypt(container, opts)
+		if err != nil {
+			skip(carrier.index, err)
+			continue
+		}
+		if len(shares) > 0 && len(share) != len(shares[0]) {
+			skip(carrier.index, fmt.Errorf("%w: share does not match the other shares", ErrCorrupted))
+			continue
+		}
+		if first == nil {
+			first, method = container, carrier.method
+		}
+		xs = append(xs, container.Share.Index)
+		shares = append(shares, share)
+		corrected += container.Corrected
+	}
+	if first == nil {
+		return nil, lastErr
+	}
+	if len(xs) < int(first.Share.Threshold) {
+		err := fmt.Errorf("%w: need %d of %d, got %d", ErrNotEnoughShares,
+			first.Share.Threshold, first.Share.Total, len(xs))
+		if len(reasons) > 0 {
+			err = fmt.Errorf("%w (%s)", err, strings.Join(reasons, "; "))
+		}
+		return nil, err
+	}
+	sort.Ints(skipped)
+
+	indices := make([]int, len(xs))
+	for i, x := range xs {
+		indices[i] = int(x)
+	}
+	sort.Ints(indices)
+	result := &ExtractResult{
+		Method:    method.Name(),
+		Version:   first.Version,
+		Encrypted: first.Flags.Has(FlagEncrypted),
+		Corrected: corrected,
+		Split: &SplitInfo{
+			SetID:     first.Share.setID(),
+			Threshold: int(first.Share.Threshold),
+			Total:     int(first.Share.Total),
+			Indices:   indices,
+			Skipped:   skipped,
+		},
+	}
+	if err := decodePayload(result, first.Flags, combineShares(xs, shares)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// shareCarrier - Mảnh đọc được từ một văn bản khi ghép
+type shareCarrier struct {
+	index     int
+	method    Method
+	container *Container
+}
+
+// shareSet - Các trường chung của mọi mảnh trong một bộ
+type shareSet struct {
+	id        [shareSetIDSize]byte
+	threshold uint8
+	total     uint8
+	flags     Flags
+}
+
+func shareSetOf(c *Container) shareSet {
+	return shareSet{id: c.Share.SetID, threshold: c.Share.Threshold, total: c.Share.Total, flags: c.Flags}
+}
+
+// largestShareSet - Bộ mảnh có nhiều vị trí khác nhau nhất; khi bằng nhau, bộ đạt số vị trí
+// đó trước được chọn
+func largestShareSet(carriers []*shareCarrier) shareSet {
+	var best shareSet
+	bestCount := 0
+	counts := make(map[shareSet]map[uint8]bool)
+	for _, carrier := range carriers {
+		set := shareSetOf(carrier.container)
+		if counts[set] == nil {
+			counts[set] = make(map[uint8]bool)
+		}
+		counts[set][carrier.container.Share.Index] = true
+		if n := len(counts[set]); n > bestCount {
+			best, bestCount = set, n
+		}
+	}
+	return best
+}
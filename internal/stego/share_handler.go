@@ -0,0 +1,72 @@
+package stego
+
+import (
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ShareSplitRequest - Request body cho chia message thành nhiều mảnh và giấu vào nhiều văn bản
+type ShareSplitRequest struct {
+	Method  string   `json:"method"`
+	Covers  []string `json:"covers" binding:"required,min=2,max=255,dive,required"`
+	Message string   `json:"message" binding:"required"`
+	// Threshold - Số mảnh cần để ghép lại, không vượt quá số văn bản phủ
+	Threshold  int         `json:"threshold" binding:"required,gte=2"`
+	Passphrase string      `json:"passphrase"`
+	ECC        *ECCRequest `json:"ecc"`
+	Scatter    bool        `json:"scatter"`
+	StegoKey   string      `json:"stego_key" binding:"required_if=Scatter true Passphrase ''"`
+}
+
+// ShareCombineRequest - Request body cho ghép message từ các văn bản mang mảnh
+type ShareCombineRequest struct {
+	Method     string   `json:"method"`
+	Texts      []string `json:"texts" binding:"required,min=1,max=255,dive,required"`
+	Passphrase string   `json:"passphrase"`
+	Scatter    bool     `json:"scatter"`
+	StegoKey   string   `json:"stego_key" binding:"required_if=Scatter true Passphrase ''"`
+}
+
+// ShareSplit - Chia message theo lược đồ Shamir và giấu mỗi mảnh vào một văn bản phủ
+func (h *Handler) ShareSplit(c *gin.Context) {
+	var req ShareSplitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+	if req.Threshold > len(req.Covers) {
+		utils.RespondWithValidationError(c, utils.FieldErrors{"threshold": ErrInvalidThreshold.Error()})
+		return
+	}
+
+	result, err := h.service.Split(req.Covers, req.Message, req.Threshold, EmbedOptions{
+		Method:     req.Method,
+		Passphrase: req.Passphrase,
+		ECC:        req.ECC.options(),
+		ScatterKey: scatterKey(req.Scatter, req.StegoKey, req.Passphrase),
+	})
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, "Message split successfully", result)
+}
+
+// ShareCombine - Ghép message từ các văn bản mang mảnh, cần ít nhất threshold mảnh
+func (h *Handler) ShareCombine(c *gin.Context) {
+	var req ShareCombineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	result, err := h.service.Combine(req.Texts, ExtractOptions{
+		Method:     req.Method,
+		Passphrase: req.Passphrase,
+		ScatterKey: scatterKey(req.Scatter, req.StegoKey, req.Passphrase),
+	})
+	respondExtracted(c, result, err)
+}
@@ -0,0 +1,311 @@
+package stego
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+)
+
+// Tên và ID của phương pháp khoảng cách và dấu câu
+const (
+	MethodSpacing   = "spacing"
+	methodIDSpacing = 10
+)
+
+// Tên các lớp thay thế của phương pháp khoảng cách
+const (
+	SpacingNBSP     = "nbsp"
+	SpacingThin     = "thin"
+	SpacingQuotes   = "quotes"
+	SpacingHyphens  = "hyphens"
+	SpacingSentence = "sentence"
+)
+
+// Các ký tự được dùng để thay thế
+const (
+	noBreakSpace     = '\u00A0'
+	thinSpace        = '\u2009'
+	figureSpace      = '\u2007'
+	noBreakHyphen    = '\u2011'
+	leftSingleQuote  = '\u2018'
+	rightSingleQuote = '\u2019'
+	leftDoubleQuote  = '\u201C'
+	rightDoubleQuote = '\u201D'
+)
+
+// SpacingClasses - Các lớp thay thế được bật. Bật càng nhiều lớp thì dung lượng càng
+// lớn nhưng văn bản càng dễ bị phát hiện.
+type SpacingClasses struct {
+	// NBSP - Dấu cách thường và dấu cách không ngắt dòng (U+00A0) giữa các từ
+	NBSP bool
+	// Thin - Thêm dấu cách hẹp (U+2009) và dấu cách chữ số (U+2007) vào bảng dấu cách
+	Thin bool
+	// Quotes - Dấu nháy thẳng và dấu nháy cong
+	Quotes bool
+	// Hyphens - Dấu gạch nối thường và gạch nối không ngắt dòng (U+2011)
+	Hyphens bool
+	// Sentence - Một hoặc hai dấu cách sau dấu kết thúc câu
+	Sentence bool
+}
+
+// AllSpacingClasses - Bật tất cả các lớp thay thế
+var AllSpacingClasses = SpacingClasses{NBSP: true, Thin: true, Quotes: true, Hyphens: true, Sentence: true}
+
+// ParseSpacingClasses - Chuyển danh sách tên lớp thành SpacingClasses, rỗng là bật tất cả
+func ParseSpacingClasses(names []string) (SpacingClasses, error) {
+	if len(names) == 0 {
+		return AllSpacingClasses, nil
+	}
+	var classes SpacingClasses
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case SpacingNBSP:
+			classes.NBSP = true
+		case SpacingThin:
+			classes.Thin = true
+		case SpacingQuotes:
+			classes.Quotes = true
+		case SpacingHyphens:
+			classes.Hyphens = true
+		case SpacingSentence:
+			classes.Sentence = true
+		default:
+			return classes, fmt.Errorf("unknown spacing class %q", name)
+		}
+	}
+	return classes, nil
+}
+
+// Spacing - Giấu dữ liệu bằng các ký tự gần như giống hệt nhau: các loại dấu cách,
+// dấu nháy thẳng/cong, gạch nối thường/không ngắt dòng và số dấu cách sau câu.
+type Spacing struct {
+	Classes SpacingClasses
+}
+
+// NewSpacing - Tạo phương pháp khoảng cách với các lớp thay thế đã chọn
+func NewSpacing(classes SpacingClasses) *Spacing {
+	return &Spacing{Classes: classes}
+}
+
+// ID - ID phương pháp trong header container
+func (s *Spacing) ID() uint8 {
+	return methodIDSpacing
+}
+
+// Name - Tên phương pháp
+func (s *Spacing) Name() string {
+	return MethodSpacing
+}
+
+// Capabilities - Đặc tính của phương pháp khoảng cách.
+// NFKC đổi các loại dấu cách đặc biệt về dấu cách thường.
+func (s *Spacing) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: false,
+		BitsPerChar:           1,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong văn bản phủ
+func (s *Spacing) Capacity(cover string) int {
+	bits := 0
+	for _, slot := range s.slots(cover) {
+		bits += slot.bits
+	}
+	return bits
+}
+
+// Embed - Thay các ký tự trong cover theo từng nhóm bit của data
+func (s *Spacing) Embed(cover string, data []byte) (string, error) {
+	if cover == "" {
+		return "", ErrEmptyCover
+	}
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > s.Capacity(cover) {
+		return "", ErrCoverTooSmall
+	}
+
+	alphabet := s.spaceAlphabet()
+	var sb strings.Builder
+	sb.Grow(len(cover) + len(bits))
+	pos := 0
+	for _, slot := range s.slots(cover) {
+		if len(bits) == 0 {
+			break
+		}
+		// Vị trí cuối có thể mang nhiều bit hơn phần còn lại, phần thiếu được đệm bằng 0
+		value := 0
+		for i := 0; i < slot.bits; i++ {
+			value <<= 1
+			if i < len(bits) {
+				value |= int(bits[i])
+			}
+		}
+		if slot.bits < len(bits) {
+			bits = bits[slot.bits:]
+		} else {
+			bits = nil
+		}
+		if value == slot.value {
+			continue
+		}
+
+		sb.WriteString(cover[pos:slot.start])
+		switch slot.kind {
+		case spacingSlotSpace:
+			sb.WriteRune(alphabet[value])
+		case spacingSlotSentence:
+			sb.WriteString(strings.Repeat(" ", value+1))
+		case spacingSlotQuote:
+			sb.WriteRune(quoteVariant(cover, slot, value))
+		case spacingSlotHyphen:
+			if value == 1 {
+				sb.WriteRune(noBreakHyphen)
+			} else {
+				sb.WriteByte('-')
+			}
+		}
+		pos = slot.end
+	}
+	sb.WriteString(cover[pos:])
+	return sb.String(), nil
+}
+
+// Extract - Đọc bit từ các ký tự thay thế theo thứ tự xuất hiện
+func (s *Spacing) Extract(text string) ([]byte, error) {
+	var bits []byte
+	for _, slot := range s.slots(text) {
+		for i := slot.bits - 1; i >= 0; i-- {
+			bits = append(bits, byte(slot.value>>uint(i))&1)
+		}
+	}
+	return bitutil.BitsToBytes(bits), nil
+}
+
+// spaceAlphabet - Bảng dấu cách giữa các từ, dấu cách thường luôn là giá trị 0
+func (s *Spacing) spaceAlphabet() []rune {
+	alphabet := []rune{' '}
+	if s.Classes.NBSP {
+		alphabet = append(alphabet, noBreakSpace)
+	}
+	if s.Classes.Thin {
+		alphabet = append(alphabet, thinSpace, figureSpace)
+	}
+	return alphabet
+}
+
+// Các loại vị trí mang tin
+const (
+	spacingSlotSpace = iota
+	spacingSlotSentence
+	spacingSlotQuote
+	spacingSlotHyphen
+)
+
+// spacingSlot - Một vị trí mang tin: vị trí byte, loại, giá trị hiện tại và số bit
+type spacingSlot struct {
+	start, end int
+	kind       int
+	value      int
+	bits       int
+}
+
+// slots - Tìm các vị trí mang tin theo thứ tự trong văn bản. Dấu cách chỉ được dùng khi
+// đứng một mình giữa hai ký tự không phải khoảng trắng; khoảng cách sau câu (dấu . ! ?
+// rồi chữ hoa) chỉ gồm một hoặc hai dấu cách thường.
+func (s *Spacing) slots(text string) []spacingSlot {
+	alphabet := s.spaceAlphabet()
+	spaceBits := setBits(len(alphabet))
+	spaceValue := func(r rune) int {
+		for i, c := range alphabet {
+			if c == r {
+				return i & (1<<uint(spaceBits) - 1)
+			}
+		}
+		return -1
+	}
+
+	var slots []spacingSlot
+	for i := 0; i < len(text); {
+		r, n := utf8.DecodeRuneInString(text[i:])
+		prev, _ := utf8.DecodeLastRuneInString(text[:i])
+
+		if spaceValue(r) >= 0 {
+			j := i
+			plain := true
+			for j < len(text) {
+				c, m := utf8.DecodeRuneInString(text[j:])
+				if spaceValue(c) < 0 {
+					break
+				}
+				plain = plain && c == ' '
+				j += m
+			}
+			runLen := utf8.RuneCountInString(text[i:j])
+			next, _ := utf8.DecodeRuneInString(text[j:])
+			if i > 0 && j < len(text) && !unicode.IsSpace(prev) && !unicode.IsSpace(next) {
+				switch {
+				case s.Classes.Sentence && strings.ContainsRune(".!?", prev) && unicode.IsUpper(next) &&
+					(runLen == 1 || (runLen == 2 && plain)):
+					slots = append(slots, spacingSlot{start: i, end: j, kind: spacingSlotSentence, value: runLen - 1, bits: 1})
+				case runLen == 1 && spaceBits > 0:
+					slots = append(slots, spacingSlot{start: i, end: j, kind: spacingSlotSpace, value: spaceValue(r), bits: spaceBits})
+				}
+			}
+			i = j
+			continue
+		}
+
+		switch {
+		case s.Classes.Quotes && strings.ContainsRune("\"'", r):
+			slots = append(slots, spacingSlot{start: i, end: i + n, kind: spacingSlotQuote, value: 0, bits: 1})
+		case s.Classes.Quotes && (r == leftSingleQuote || r == rightSingleQuote || r == leftDoubleQuote || r == rightDoubleQuote):
+			slots = append(slots, spacingSlot{start: i, end: i + n, kind: spacingSlotQuote, value: 1, bits: 1})
+		case s.Classes.Hyphens && (r == '-' || r == noBreakHyphen) && i > 0 && i+n < len(text):
+			next, _ := utf8.DecodeRuneInString(text[i+n:])
+			if isWordRune(prev) && isWordRune(next) {
+				value := 0
+				if r == noBreakHyphen {
+					value = 1
+				}
+				slots = append(slots, spacingSlot{start: i, end: i + n, kind: spacingSlotHyphen, value: value, bits: 1})
+			}
+		}
+		i += n
+	}
+	return slots
+}
+
+// quoteVariant - Dấu nháy thay thế: dạng thẳng cho giá trị 0, dạng cong mở/đóng theo
+// ngữ cảnh cho giá trị 1
+func quoteVariant(text string, slot spacingSlot, value int) rune {
+	r, _ := utf8.DecodeRuneInString(text[slot.start:])
+	double := r == '"' || r == leftDoubleQuote || r == rightDoubleQuote
+	if value == 0 {
+		if double {
+			return '"'
+		}
+		return '\''
+	}
+
+	prev, _ := utf8.DecodeLastRuneInString(text[:slot.start])
+	opening := slot.start == 0 || unicode.IsSpace(prev) || strings.ContainsRune("([{<-\u2013\u2014", prev)
+	switch {
+	case double && opening:
+		return leftDoubleQuote
+	case double:
+		return rightDoubleQuote
+	case opening:
+		return leftSingleQuote
+	default:
+		return rightSingleQuote
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
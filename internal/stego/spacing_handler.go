@@ -0,0 +1,67 @@
+package stego
+
+import (
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SpacingFields - Các lớp thay thế dùng cho request. Khi trích xuất phải dùng đúng
+// các lớp đã dùng khi giấu tin; để trống là bật tất cả.
+type SpacingFields struct {
+	Classes []string `json:"classes" binding:"omitempty,dive,oneof=nbsp thin quotes hyphens sentence"`
+}
+
+// method - Tạo phương pháp theo các lớp trong request
+func (f *SpacingFields) method() (*Spacing, error) {
+	classes, err := ParseSpacingClasses(f.Classes)
+	if err != nil {
+		return nil, err
+	}
+	return NewSpacing(classes), nil
+}
+
+// SpacingEmbedRequest - Request body cho giấu tin bằng khoảng cách và dấu câu
+type SpacingEmbedRequest struct {
+	EmbedFields
+	SpacingFields
+}
+
+// SpacingExtractRequest - Request body cho trích xuất tin từ khoảng cách và dấu câu
+type SpacingExtractRequest struct {
+	ExtractFields
+	SpacingFields
+}
+
+// SpacingEmbed - Giấu message bằng các loại dấu cách, dấu nháy và gạch nối
+func (h *Handler) SpacingEmbed(c *gin.Context) {
+	var req SpacingEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodSpacing))
+	respondEmbedded(c, text, err)
+}
+
+// SpacingExtract - Trích xuất message từ các loại dấu cách, dấu nháy và gạch nối
+func (h *Handler) SpacingExtract(c *gin.Context) {
+	var req SpacingExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	result, err := h.service.ExtractWithMethod(method, req.Text, req.options(MethodSpacing))
+	respondExtracted(c, result, err)
+}
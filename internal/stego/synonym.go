@@ -0,0 +1,245 @@
+package stego
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tên và ID của phương pháp thay từ đồng nghĩa. Từ điển tiếng Việt và tiếng Anh có ID
+// riêng để tự nhận diện khi trích xuất; từ điển tùy chỉnh dùng chung một ID và phải được
+// chỉ định lại khi trích xuất.
+const (
+	MethodSynonymVietnamese = "synonym-vi"
+	MethodSynonymEnglish    = "synonym-en"
+	MethodSynonymCustom     = "synonym-custom"
+
+	methodIDSynonymVietnamese = 7
+	methodIDSynonymEnglish    = 8
+	methodIDSynonymCustom     = 9
+)
+
+// ErrAmbiguousSynonyms - Việc thay từ làm thay đổi cách tách từ nên không trích xuất lại được
+var ErrAmbiguousSynonyms = errors.New("synonym substitution is ambiguous for this cover text")
+
+// Synonym - Giấu dữ liệu bằng cách chọn từ trong các nhóm từ đồng nghĩa
+// ("nhưng/tuy nhiên", "big/large"). Nhóm có n từ mang floor(log2 n) bit: vị trí
+// của từ được chọn trong nhóm đã sắp xếp là giá trị các bit.
+type Synonym struct {
+	Dictionary *SynonymDictionary
+}
+
+// NewSynonym - Tạo phương pháp thay từ đồng nghĩa với một từ điển
+func NewSynonym(dictionary *SynonymDictionary) *Synonym {
+	return &Synonym{Dictionary: dictionary}
+}
+
+// ID - ID phương pháp trong header container
+func (s *Synonym) ID() uint8 {
+	switch s.Dictionary.Language {
+	case "vi":
+		return methodIDSynonymVietnamese
+	case "en":
+		return methodIDSynonymEnglish
+	}
+	return methodIDSynonymCustom
+}
+
+// Name - Tên phương pháp
+func (s *Synonym) Name() string {
+	switch s.Dictionary.Language {
+	case "vi":
+		return MethodSynonymVietnamese
+	case "en":
+		return MethodSynonymEnglish
+	}
+	return MethodSynonymCustom
+}
+
+// Capabilities - Đặc tính của phương pháp thay từ đồng nghĩa
+func (s *Synonym) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: true,
+		BitsPerChar:           0.1,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong văn bản phủ
+func (s *Synonym) Capacity(cover string) int {
+	bits := 0
+	for _, slot := range s.slots(cover) {
+		bits += slot.bits
+	}
+	return bits
+}
+
+// Embed - Thay các từ trong cover bằng từ đồng nghĩa theo từng nhóm bit của data
+func (s *Synonym) Embed(cover string, data []byte) (string, error) {
+	if cover == "" {
+		return "", ErrEmptyCover
+	}
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > s.Capacity(cover) {
+		return "", ErrCoverTooSmall
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(cover))
+	pos := 0
+	for _, slot := range s.slots(cover) {
+		if len(bits) == 0 {
+			break
+		}
+		// Nhóm cuối có thể mang nhiều bit hơn phần còn lại, phần thiếu được đệm bằng 0
+		value := 0
+		for i := 0; i < slot.bits; i++ {
+			value <<= 1
+			if i < len(bits) {
+				value |= int(bits[i])
+			}
+		}
+		if slot.bits < len(bits) {
+			bits = bits[slot.bits:]
+		} else {
+			bits = nil
+		}
+		if value == slot.value {
+			continue
+		}
+		sb.WriteString(cover[pos:slot.start])
+		sb.WriteString(matchCase(cover[slot.start:slot.end], s.Dictionary.sets[slot.set][value]))
+		pos = slot.end
+	}
+	sb.WriteString(cover[pos:])
+	text := sb.String()
+
+	// Kiểm tra lại vì thay một cụm từ có thể làm thay đổi cách tách các từ bên cạnh
+	extracted, err := s.Extract(text)
+	if err != nil {
+		return "", err
+	}
+	if len(extracted) < len(data) || !bytes.Equal(extracted[:len(data)], data) {
+		return "", ErrAmbiguousSynonyms
+	}
+	return text, nil
+}
+
+// Extract - Đọc vị trí của từng từ trong nhóm đồng nghĩa của nó
+func (s *Synonym) Extract(text string) ([]byte, error) {
+	var bits []byte
+	for _, slot := range s.slots(text) {
+		for i := slot.bits - 1; i >= 0; i-- {
+			bits = append(bits, byte(slot.value>>uint(i))&1)
+		}
+	}
+	return bitutil.BitsToBytes(bits), nil
+}
+
+// SentenceCapacity - Dung lượng của từng câu trong văn bản phủ
+func (s *Synonym) SentenceCapacity(cover string) []SentenceCapacity {
+	sentences := splitSentences(cover)
+	capacities := make([]SentenceCapacity, len(sentences))
+	for i, sentence := range sentences {
+		capacities[i].Sentence = cover[sentence.start:sentence.end]
+	}
+
+	i := 0
+	for _, slot := range s.slots(cover) {
+		for i < len(sentences)-1 && slot.start >= sentences[i].end {
+			i++
+		}
+		if i < len(sentences) {
+			capacities[i].Bits += slot.bits
+		}
+	}
+	return capacities
+}
+
+// synonymSlot - Một từ/cụm từ mang tin: vị trí byte, nhóm, vị trí trong nhóm và số bit
+type synonymSlot struct {
+	start, end int
+	set        int
+	value      int
+	bits       int
+}
+
+// slots - Tìm các từ trong từ điển, ưu tiên cụm dài nhất tại mỗi vị trí.
+// Các tiếng trong một cụm phải cách nhau đúng một dấu cách.
+func (s *Synonym) slots(text string) []synonymSlot {
+	d := s.Dictionary
+	tokens := synonymTokens(text)
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = strings.ToLower(norm.NFC.String(text[token.start:token.end]))
+	}
+
+	var slots []synonymSlot
+	for i := 0; i < len(tokens); {
+		matched := 0
+		for n := d.maxWords; n >= 1; n-- {
+			if i+n > len(tokens) || !singleSpaced(text, tokens[i:i+n]) {
+				continue
+			}
+			entry, ok := d.index[strings.Join(words[i:i+n], " ")]
+			if !ok {
+				continue
+			}
+			matched = n
+			bits := setBits(len(d.sets[entry.set]))
+			if entry.pos < 1<<uint(bits) {
+				slots = append(slots, synonymSlot{
+					start: tokens[i].start,
+					end:   tokens[i+n-1].end,
+					set:   entry.set,
+					value: entry.pos,
+					bits:  bits,
+				})
+			}
+			break
+		}
+		if matched == 0 {
+			matched = 1
+		}
+		i += matched
+	}
+	return slots
+}
+
+// singleSpaced - Kiểm tra các tiếng liên tiếp chỉ cách nhau một dấu cách
+func singleSpaced(text string, tokens []textRange) bool {
+	for i := 1; i < len(tokens); i++ {
+		if text[tokens[i-1].end:tokens[i].start] != " " {
+			return false
+		}
+	}
+	return true
+}
+
+// matchCase - Viết từ thay thế theo kiểu chữ hoa/thường của từ gốc
+func matchCase(original, replacement string) string {
+	letters := 0
+	upper := 0
+	for _, r := range original {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters > 1 && upper == letters {
+		return strings.ToUpper(replacement)
+	}
+	first, _ := utf8.DecodeRuneInString(original)
+	if unicode.IsUpper(first) {
+		r, n := utf8.DecodeRuneInString(replacement)
+		return string(unicode.ToUpper(r)) + replacement[n:]
+	}
+	return replacement
+}
@@ -0,0 +1,254 @@
+package stego
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed dictionaries/*.txt
+var bundledDictionaries embed.FS
+
+// Giới hạn của từ điển đồng nghĩa
+const (
+	// maxSynonymBits - Số bit tối đa một nhóm từ mang được (256 từ mỗi nhóm)
+	maxSynonymBits = 8
+	// maxSynonymWords - Số tiếng tối đa của một cụm từ
+	maxSynonymWords = 6
+)
+
+// synonymEntry - Vị trí của một từ/cụm từ trong từ điển
+type synonymEntry struct {
+	set int
+	pos int
+}
+
+// SynonymDictionary - Từ điển các nhóm từ đồng nghĩa của một ngôn ngữ.
+// Mỗi nhóm được sắp xếp theo thứ tự byte của dạng chữ thường NFC nên thứ tự
+// không phụ thuộc vào file nguồn, và bên trích xuất chỉ cần cùng từ điển.
+type SynonymDictionary struct {
+	Language string
+
+	sets     [][]string
+	index    map[string]synonymEntry
+	maxWords int
+}
+
+// ParseSynonymDictionary - Đọc từ điển dạng văn bản: mỗi dòng là một nhóm từ cách nhau
+// bởi dấu phẩy, dòng bắt đầu bằng "#" là chú thích. Một tiếng chỉ được thuộc về một nhóm
+// và trong một nhóm không cụm nào được chứa cụm khác, để việc thay từ không làm
+// thay đổi cách tách các từ xung quanh.
+func ParseSynonymDictionary(language string, r io.Reader) (*SynonymDictionary, error) {
+	d := &SynonymDictionary{
+		Language: language,
+		index:    make(map[string]synonymEntry),
+	}
+	tokenSets := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var set []string
+		seen := make(map[string]bool)
+		for _, field := range strings.Split(text, ",") {
+			word := normalizeSynonym(field)
+			if word == "" || seen[word] {
+				continue
+			}
+			seen[word] = true
+			set = append(set, word)
+		}
+		if len(set) < 2 {
+			return nil, fmt.Errorf("line %d: a synonym set needs at least 2 distinct entries", line)
+		}
+		if len(set) > 1<<maxSynonymBits {
+			return nil, fmt.Errorf("line %d: a synonym set has at most %d entries", line, 1<<maxSynonymBits)
+		}
+		sort.Strings(set)
+
+		setIndex := len(d.sets)
+		for pos, word := range set {
+			if _, exists := d.index[word]; exists {
+				return nil, fmt.Errorf("line %d: %q already appears in another set", line, word)
+			}
+			tokens := strings.Fields(word)
+			if len(tokens) > maxSynonymWords {
+				return nil, fmt.Errorf("line %d: %q has more than %d words", line, word, maxSynonymWords)
+			}
+			for _, token := range tokens {
+				if other, ok := tokenSets[token]; ok && other != setIndex {
+					return nil, fmt.Errorf("line %d: word %q is shared with the set on another line", line, token)
+				}
+				tokenSets[token] = setIndex
+			}
+			for _, other := range set {
+				if other != word && strings.Contains(" "+other+" ", " "+word+" ") {
+					return nil, fmt.Errorf("line %d: %q is contained in %q", line, word, other)
+				}
+			}
+			d.index[word] = synonymEntry{set: setIndex, pos: pos}
+			if len(tokens) > d.maxWords {
+				d.maxWords = len(tokens)
+			}
+		}
+		d.sets = append(d.sets, set)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(d.sets) == 0 {
+		return nil, fmt.Errorf("dictionary %q has no synonym sets", language)
+	}
+	return d, nil
+}
+
+// Sets - Số nhóm từ trong từ điển
+func (d *SynonymDictionary) Sets() int {
+	return len(d.sets)
+}
+
+// normalizeSynonym - Chữ thường, NFC, các tiếng cách nhau đúng một dấu cách
+func normalizeSynonym(word string) string {
+	return strings.Join(strings.Fields(strings.ToLower(norm.NFC.String(word))), " ")
+}
+
+// setBits - Số bit nhóm từ mang được: floor(log2(số từ))
+func setBits(size int) int {
+	bits := 0
+	for 1<<(bits+1) <= size && bits < maxSynonymBits {
+		bits++
+	}
+	return bits
+}
+
+// SynonymLibrary - Các từ điển đồng nghĩa theo ngôn ngữ
+type SynonymLibrary struct {
+	dictionaries map[string]*SynonymDictionary
+}
+
+// SynonymLanguageInfo - Thông tin một từ điển trong thư viện
+type SynonymLanguageInfo struct {
+	Language string `json:"language"`
+	Sets     int    `json:"sets"`
+}
+
+var (
+	bundledSynonymsOnce sync.Once
+	bundledSynonyms     *SynonymLibrary
+)
+
+// BundledSynonyms - Thư viện các từ điển đi kèm ứng dụng
+func BundledSynonyms() *SynonymLibrary {
+	bundledSynonymsOnce.Do(func() {
+		library, err := loadSynonymFiles(bundledDictionaries, "dictionaries")
+		if err != nil {
+			// Từ điển đi kèm được kiểm tra khi phát triển, lỗi ở đây là lỗi build
+			panic(fmt.Sprintf("invalid bundled synonym dictionary: %v", err))
+		}
+		bundledSynonyms = library
+	})
+	return bundledSynonyms
+}
+
+// LoadSynonymLibrary - Thư viện gồm các từ điển đi kèm và các file <ngôn ngữ>.txt trong dir.
+// File trong dir thay thế từ điển đi kèm cùng ngôn ngữ. dir rỗng thì chỉ dùng từ điển đi kèm.
+func LoadSynonymLibrary(dir string) (*SynonymLibrary, error) {
+	library := &SynonymLibrary{dictionaries: make(map[string]*SynonymDictionary)}
+	for language, dictionary := range BundledSynonyms().dictionaries {
+		library.dictionaries[language] = dictionary
+	}
+	if dir == "" {
+		return library, nil
+	}
+
+	custom, err := loadSynonymFiles(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, err
+	}
+	for language, dictionary := range custom.dictionaries {
+		library.dictionaries[language] = dictionary
+	}
+	return library, nil
+}
+
+// loadSynonymFiles - Đọc mọi file .txt trong thư mục, tên file là mã ngôn ngữ
+func loadSynonymFiles(fsys fs.FS, dir string) (*SynonymLibrary, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	library := &SynonymLibrary{dictionaries: make(map[string]*SynonymDictionary)}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".txt" {
+			continue
+		}
+		language := strings.ToLower(strings.TrimSuffix(entry.Name(), ".txt"))
+		file, err := fsys.Open(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		dictionary, err := ParseSynonymDictionary(language, file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		library.dictionaries[language] = dictionary
+	}
+	return library, nil
+}
+
+// Get - Lấy từ điển theo mã ngôn ngữ
+func (l *SynonymLibrary) Get(language string) (*SynonymDictionary, error) {
+	dictionary, ok := l.dictionaries[strings.ToLower(language)]
+	if !ok {
+		return nil, fmt.Errorf("no synonym dictionary for language %q", language)
+	}
+	return dictionary, nil
+}
+
+// Languages - Danh sách từ điển theo mã ngôn ngữ
+func (l *SynonymLibrary) Languages() []SynonymLanguageInfo {
+	infos := make([]SynonymLanguageInfo, 0, len(l.dictionaries))
+	for language, dictionary := range l.dictionaries {
+		infos = append(infos, SynonymLanguageInfo{Language: language, Sets: dictionary.Sets()})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Language < infos[j].Language
+	})
+	return infos
+}
+
+// synonymTokens - Tách văn bản thành các tiếng (chữ, số và dấu kết hợp)
+func synonymTokens(text string) []textRange {
+	var tokens []textRange
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			tokens = append(tokens, textRange{start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, textRange{start: start, end: len(text)})
+	}
+	return tokens
+}
@@ -0,0 +1,90 @@
+package stego
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SynonymFields - Từ điển dùng cho request: từ điển theo ngôn ngữ trong thư viện,
+// hoặc một từ điển tùy chỉnh gửi kèm. Khi trích xuất phải dùng đúng từ điển đã dùng khi giấu tin.
+type SynonymFields struct {
+	// Language - Mã ngôn ngữ, mặc định "vi"
+	Language string `json:"language"`
+	// Dictionary - Nội dung từ điển tùy chỉnh, cùng định dạng với file từ điển
+	Dictionary string `json:"dictionary"`
+}
+
+// method - Tạo phương pháp theo từ điển trong request
+func (f *SynonymFields) method(library *SynonymLibrary) (*Synonym, error) {
+	if f.Dictionary != "" {
+		dictionary, err := ParseSynonymDictionary("custom", strings.NewReader(f.Dictionary))
+		if err != nil {
+			return nil, fmt.Errorf("invalid dictionary: %w", err)
+		}
+		return NewSynonym(dictionary), nil
+	}
+	language := f.Language
+	if language == "" {
+		language = "vi"
+	}
+	dictionary, err := library.Get(language)
+	if err != nil {
+		return nil, err
+	}
+	return NewSynonym(dictionary), nil
+}
+
+// SynonymEmbedRequest - Request body cho giấu tin bằng từ đồng nghĩa
+type SynonymEmbedRequest struct {
+	EmbedFields
+	SynonymFields
+}
+
+// SynonymExtractRequest - Request body cho trích xuất tin từ từ đồng nghĩa
+type SynonymExtractRequest struct {
+	ExtractFields
+	SynonymFields
+}
+
+// SynonymLanguages - Danh sách từ điển đồng nghĩa có sẵn
+func (h *Handler) SynonymLanguages(c *gin.Context) {
+	utils.RespondWithSuccess(c, http.StatusOK, "Synonym dictionaries retrieved successfully", h.service.Synonyms().Languages())
+}
+
+// SynonymEmbed - Giấu message bằng cách chọn từ đồng nghĩa
+func (h *Handler) SynonymEmbed(c *gin.Context) {
+	var req SynonymEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method(h.service.Synonyms())
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(method.Name()))
+	respondEmbedded(c, text, err)
+}
+
+// SynonymExtract - Trích xuất message từ các từ đồng nghĩa đã chọn
+func (h *Handler) SynonymExtract(c *gin.Context) {
+	var req SynonymExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method(h.service.Synonyms())
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	result, err := h.service.ExtractWithMethod(method, req.Text, req.options(method.Name()))
+	respondExtracted(c, result, err)
+}
@@ -0,0 +1,306 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tên và ID của phương pháp vị trí dấu thanh
+const (
+	MethodToneMark   = "tone-mark"
+	methodIDToneMark = 5
+)
+
+// ErrMixedToneStyles - Văn bản phủ dùng cả hai kiểu bỏ dấu cho cùng một âm tiết
+var ErrMixedToneStyles = errors.New("cover mixes old and new tone placement for the same syllable")
+
+// Kiểu bỏ dấu thanh: kiểu cũ đặt dấu ở nguyên âm đầu (hòa), kiểu mới ở nguyên âm sau (hoà)
+const (
+	toneStyleOld byte = 0
+	toneStyleNew byte = 1
+)
+
+// toneRule - Một cụm nguyên âm có hai cách đặt dấu thanh đều được chấp nhận
+type toneRule struct {
+	cluster string
+	old     string
+	new     string
+}
+
+// toneRules - Các cụm nguyên âm hợp lệ. Cụm chỉ được dùng khi đứng cuối âm tiết
+// (không có phụ âm cuối) và không đi sau "q" (trong "quý" dấu luôn ở "y").
+var toneRules = []toneRule{
+	{cluster: "oa", old: "hòa", new: "hoà"},
+	{cluster: "oe", old: "khỏe", new: "khoẻ"},
+	{cluster: "uy", old: "thúy", new: "thuý"},
+}
+
+// toneMarks - Các dấu thanh dạng ký tự kết hợp: huyền, sắc, ngã, hỏi, nặng
+var toneMarks = map[rune]bool{
+	'\u0300': true,
+	'\u0301': true,
+	'\u0303': true,
+	'\u0309': true,
+	'\u0323': true,
+}
+
+// ToneRuleInfo - Thông tin một cụm nguyên âm trong bảng quy tắc
+type ToneRuleInfo struct {
+	Cluster string `json:"cluster"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// ToneRules - Bảng quy tắc các cụm nguyên âm dùng để giấu tin
+func ToneRules() []ToneRuleInfo {
+	infos := make([]ToneRuleInfo, 0, len(toneRules))
+	for _, rule := range toneRules {
+		infos = append(infos, ToneRuleInfo{Cluster: rule.cluster, Old: rule.old, New: rule.new})
+	}
+	return infos
+}
+
+// ToneMark - Giấu dữ liệu bằng vị trí dấu thanh trong các âm tiết tiếng Việt như
+// "hòa/hoà", "thúy/thuý", "khỏe/khoẻ". Kiểu cũ là bit 0, kiểu mới là bit 1.
+// Mỗi âm tiết khác nhau mang 1 bit và mọi lần xuất hiện của nó được viết cùng một
+// kiểu, để văn bản không dùng lẫn hai kiểu cho cùng một chữ.
+// Dung lượng rất thấp: văn bản tiếng Việt thông thường chỉ có vài âm tiết hợp lệ khác
+// nhau trong một nghìn chữ, trong khi header container đã cần ít nhất 104 bit, nên phương
+// pháp chỉ dùng được với văn bản phủ có nhiều âm tiết khác nhau như vậy.
+type ToneMark struct{}
+
+// NewToneMark - Tạo phương pháp vị trí dấu thanh
+func NewToneMark() *ToneMark {
+	return &ToneMark{}
+}
+
+// ID - ID phương pháp trong header container
+func (t *ToneMark) ID() uint8 {
+	return methodIDToneMark
+}
+
+// Name - Tên phương pháp
+func (t *ToneMark) Name() string {
+	return MethodToneMark
+}
+
+// Capabilities - Đặc tính của phương pháp vị trí dấu thanh
+func (t *ToneMark) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: true,
+		BitsPerChar:           1,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu, bằng số âm tiết hợp lệ khác nhau
+func (t *ToneMark) Capacity(cover string) int {
+	_, keys := toneSyllables(cover)
+	return len(keys)
+}
+
+// Embed - Đặt lại dấu thanh của các âm tiết theo từng bit của data
+func (t *ToneMark) Embed(cover string, data []byte) (string, error) {
+	if cover == "" {
+		return "", ErrEmptyCover
+	}
+	syllables, keys := toneSyllables(cover)
+	if mixed := mixedToneSyllables(syllables); len(mixed) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrMixedToneStyles, strings.Join(mixed, ", "))
+	}
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > len(keys) {
+		return "", ErrCoverTooSmall
+	}
+
+	styles := make(map[string]byte, len(bits))
+	for i, bit := range bits {
+		styles[keys[i]] = bit
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(cover))
+	pos := 0
+	for _, s := range syllables {
+		style, ok := styles[s.key]
+		if !ok || style == s.style {
+			continue
+		}
+		sb.WriteString(cover[pos:s.start])
+		sb.WriteString(s.render(style))
+		pos = s.end
+	}
+	sb.WriteString(cover[pos:])
+	return sb.String(), nil
+}
+
+// Extract - Đọc kiểu bỏ dấu tại lần xuất hiện đầu tiên của mỗi âm tiết
+func (t *ToneMark) Extract(text string) ([]byte, error) {
+	syllables, keys := toneSyllables(text)
+	first := make(map[string]byte, len(keys))
+	for _, s := range syllables {
+		if _, ok := first[s.key]; !ok {
+			first[s.key] = s.style
+		}
+	}
+	bits := make([]byte, len(keys))
+	for i, key := range keys {
+		bits[i] = first[key]
+	}
+	return bitutil.BitsToBytes(bits), nil
+}
+
+// toneSyllable - Một âm tiết mang tin: vị trí byte, khóa (âm tiết chữ thường kèm
+// dấu thanh, không phụ thuộc vị trí dấu) và kiểu bỏ dấu hiện tại
+type toneSyllable struct {
+	start, end int
+	key        string
+	style      byte
+
+	onset  string // phụ âm đầu (NFD)
+	first  rune
+	second rune
+	tone   rune
+}
+
+// render - Viết lại âm tiết theo kiểu bỏ dấu, giữ nguyên chữ hoa/thường
+func (s *toneSyllable) render(style byte) string {
+	var sb strings.Builder
+	sb.WriteString(s.onset)
+	sb.WriteRune(s.first)
+	if style == toneStyleOld {
+		sb.WriteRune(s.tone)
+	}
+	sb.WriteRune(s.second)
+	if style == toneStyleNew {
+		sb.WriteRune(s.tone)
+	}
+	return norm.NFC.String(sb.String())
+}
+
+// toneSyllables - Tìm các âm tiết hợp lệ và danh sách khóa theo thứ tự xuất hiện đầu tiên
+func toneSyllables(text string) ([]toneSyllable, []string) {
+	var syllables []toneSyllable
+	var keys []string
+	seen := make(map[string]bool)
+
+	for i := 0; i < len(text); {
+		r, n := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsLetter(r) {
+			i += n
+			continue
+		}
+		end := i + n
+		for end < len(text) {
+			next, m := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsLetter(next) && !unicode.Is(unicode.Mn, next) {
+				break
+			}
+			end += m
+		}
+		if s, ok := parseToneSyllable(text[i:end]); ok {
+			s.start, s.end = i, end
+			syllables = append(syllables, s)
+			if !seen[s.key] {
+				seen[s.key] = true
+				keys = append(keys, s.key)
+			}
+		}
+		i = end
+	}
+	return syllables, keys
+}
+
+// parseToneSyllable - Kiểm tra một từ có dạng [phụ âm đầu] + cụm nguyên âm + dấu thanh không
+func parseToneSyllable(word string) (toneSyllable, bool) {
+	type letter struct {
+		base  rune
+		marks []rune
+	}
+	var letters []letter
+	for _, r := range norm.NFD.String(word) {
+		if unicode.Is(unicode.Mn, r) {
+			if len(letters) == 0 {
+				return toneSyllable{}, false
+			}
+			letters[len(letters)-1].marks = append(letters[len(letters)-1].marks, r)
+			continue
+		}
+		letters = append(letters, letter{base: r})
+	}
+	n := len(letters)
+	if n < 2 || n > 5 {
+		return toneSyllable{}, false
+	}
+
+	first, second := letters[n-2], letters[n-1]
+	cluster := string(unicode.ToLower(first.base)) + string(unicode.ToLower(second.base))
+	if !isToneCluster(cluster) {
+		return toneSyllable{}, false
+	}
+
+	// Cụm nguyên âm phải có đúng một dấu thanh và không có dấu phụ (ă, â, ê, ơ...)
+	if len(first.marks)+len(second.marks) != 1 {
+		return toneSyllable{}, false
+	}
+	s := toneSyllable{first: first.base, second: second.base, style: toneStyleOld}
+	if len(first.marks) == 1 {
+		s.tone = first.marks[0]
+	} else {
+		s.tone = second.marks[0]
+		s.style = toneStyleNew
+	}
+	if !toneMarks[s.tone] {
+		return toneSyllable{}, false
+	}
+
+	var onset strings.Builder
+	for i, l := range letters[:n-2] {
+		lower := unicode.ToLower(l.base)
+		if len(l.marks) > 0 || !strings.ContainsRune("bcdđghklmnpqrstvx", lower) {
+			return toneSyllable{}, false
+		}
+		if i == n-3 && lower == 'q' {
+			return toneSyllable{}, false
+		}
+		onset.WriteRune(l.base)
+	}
+	s.onset = onset.String()
+	s.key = strings.ToLower(s.onset) + cluster + string(s.tone)
+	return s, true
+}
+
+func isToneCluster(cluster string) bool {
+	for _, rule := range toneRules {
+		if rule.cluster == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// mixedToneSyllables - Các âm tiết xuất hiện với cả hai kiểu bỏ dấu
+func mixedToneSyllables(syllables []toneSyllable) []string {
+	first := make(map[string]*toneSyllable)
+	reported := make(map[string]bool)
+	var mixed []string
+	for i := range syllables {
+		s := &syllables[i]
+		prev, ok := first[s.key]
+		if !ok {
+			first[s.key] = s
+			continue
+		}
+		if prev.style != s.style && !reported[s.key] {
+			reported[s.key] = true
+			mixed = append(mixed, strings.ToLower(prev.render(toneStyleOld))+"/"+strings.ToLower(prev.render(toneStyleNew)))
+		}
+	}
+	return mixed
+}
@@ -0,0 +1,37 @@
+package stego
+
+import (
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ToneMarkRules - Bảng quy tắc các cụm nguyên âm dùng để giấu tin bằng vị trí dấu thanh
+func (h *Handler) ToneMarkRules(c *gin.Context) {
+	utils.RespondWithSuccess(c, http.StatusOK, "Tone mark rules retrieved successfully", ToneRules())
+}
+
+// ToneMarkEmbed - Giấu message bằng vị trí dấu thanh trong văn bản tiếng Việt
+func (h *Handler) ToneMarkEmbed(c *gin.Context) {
+	var req EmbedFields
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	text, err := h.service.Embed(req.Cover, req.Message, req.options(MethodToneMark))
+	respondEmbedded(c, text, err)
+}
+
+// ToneMarkExtract - Trích xuất message từ vị trí dấu thanh
+func (h *Handler) ToneMarkExtract(c *gin.Context) {
+	var req ExtractFields
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	result, err := h.service.Extract(req.Text, req.options(MethodToneMark))
+	respondExtracted(c, result, err)
+}
@@ -0,0 +1,410 @@
+package stego
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tên và ID của phương pháp variation selector
+const (
+	MethodVariationSelector   = "variation-selector"
+	methodIDVariationSelector = 4
+)
+
+// DefaultVariationBytesPerHost - Số byte tối đa gắn vào một emoji/ký tự
+const DefaultVariationBytesPerHost = 1024
+
+// Các ký tự dùng trong chuỗi emoji
+const (
+	textPresentation  = '\uFE0E'
+	emojiPresentation = '\uFE0F'
+	emojiJoiner       = '\u200D'
+	combiningKeycap   = '\u20E3'
+)
+
+// emojiBases - Các ký tự emoji có thể đứng đầu một cụm emoji (không gồm
+// regional indicator và skin tone modifier vì chúng được xử lý riêng)
+var emojiBases = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00A9, Hi: 0x00A9, Stride: 1},
+		{Lo: 0x00AE, Hi: 0x00AE, Stride: 1},
+		{Lo: 0x203C, Hi: 0x203C, Stride: 1},
+		{Lo: 0x2049, Hi: 0x2049, Stride: 1},
+		{Lo: 0x2122, Hi: 0x2122, Stride: 1},
+		{Lo: 0x2139, Hi: 0x2139, Stride: 1},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21A9, Hi: 0x21AA, Stride: 1},
+		{Lo: 0x231A, Hi: 0x231B, Stride: 1},
+		{Lo: 0x2328, Hi: 0x2328, Stride: 1},
+		{Lo: 0x23CF, Hi: 0x23CF, Stride: 1},
+		{Lo: 0x23E9, Hi: 0x23F3, Stride: 1},
+		{Lo: 0x23F8, Hi: 0x23FA, Stride: 1},
+		{Lo: 0x24C2, Hi: 0x24C2, Stride: 1},
+		{Lo: 0x25AA, Hi: 0x25AB, Stride: 1},
+		{Lo: 0x25B6, Hi: 0x25B6, Stride: 1},
+		{Lo: 0x25C0, Hi: 0x25C0, Stride: 1},
+		{Lo: 0x25FB, Hi: 0x25FE, Stride: 1},
+		{Lo: 0x2600, Hi: 0x27BF, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2B05, Hi: 0x2B07, Stride: 1},
+		{Lo: 0x2B1B, Hi: 0x2B1C, Stride: 1},
+		{Lo: 0x2B50, Hi: 0x2B50, Stride: 1},
+		{Lo: 0x2B55, Hi: 0x2B55, Stride: 1},
+		{Lo: 0x3030, Hi: 0x3030, Stride: 1},
+		{Lo: 0x303D, Hi: 0x303D, Stride: 1},
+		{Lo: 0x3297, Hi: 0x3297, Stride: 1},
+		{Lo: 0x3299, Hi: 0x3299, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1F000, Hi: 0x1F1E5, Stride: 1},
+		{Lo: 0x1F200, Hi: 0x1F3FA, Stride: 1},
+		{Lo: 0x1F400, Hi: 0x1FAFF, Stride: 1},
+	},
+	LatinOffset: 2,
+}
+
+// emojiPresentationBases - Các ký tự mặc định hiển thị dạng emoji (Emoji_Presentation=Yes
+// theo emoji-data.txt). Các ký tự còn lại trong emojiBases mặc định hiển thị dạng chữ.
+var emojiPresentationBases = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x231A, Hi: 0x231B, Stride: 1},
+		{Lo: 0x23E9, Hi: 0x23EC, Stride: 1},
+		{Lo: 0x23F0, Hi: 0x23F0, Stride: 1},
+		{Lo: 0x23F3, Hi: 0x23F3, Stride: 1},
+		{Lo: 0x25FD, Hi: 0x25FE, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2615, Stride: 1},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 1},
+		{Lo: 0x267F, Hi: 0x267F, Stride: 1},
+		{Lo: 0x2693, Hi: 0x2693, Stride: 1},
+		{Lo: 0x26A1, Hi: 0x26A1, Stride: 1},
+		{Lo: 0x26AA, Hi: 0x26AB, Stride: 1},
+		{Lo: 0x26BD, Hi: 0x26BE, Stride: 1},
+		{Lo: 0x26C4, Hi: 0x26C5, Stride: 1},
+		{Lo: 0x26CE, Hi: 0x26CE, Stride: 1},
+		{Lo: 0x26D4, Hi: 0x26D4, Stride: 1},
+		{Lo: 0x26EA, Hi: 0x26EA, Stride: 1},
+		{Lo: 0x26F2, Hi: 0x26F3, Stride: 1},
+		{Lo: 0x26F5, Hi: 0x26F5, Stride: 1},
+		{Lo: 0x26FA, Hi: 0x26FA, Stride: 1},
+		{Lo: 0x26FD, Hi: 0x26FD, Stride: 1},
+		{Lo: 0x2705, Hi: 0x2705, Stride: 1},
+		{Lo: 0x270A, Hi: 0x270B, Stride: 1},
+		{Lo: 0x2728, Hi: 0x2728, Stride: 1},
+		{Lo: 0x274C, Hi: 0x274C, Stride: 1},
+		{Lo: 0x274E, Hi: 0x274E, Stride: 1},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2757, Hi: 0x2757, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x27B0, Hi: 0x27B0, Stride: 1},
+		{Lo: 0x27BF, Hi: 0x27BF, Stride: 1},
+		{Lo: 0x2B1B, Hi: 0x2B1C, Stride: 1},
+		{Lo: 0x2B50, Hi: 0x2B50, Stride: 1},
+		{Lo: 0x2B55, Hi: 0x2B55, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1F004, Hi: 0x1F004, Stride: 1},
+		{Lo: 0x1F0CF, Hi: 0x1F0CF, Stride: 1},
+		{Lo: 0x1F18E, Hi: 0x1F18E, Stride: 1},
+		{Lo: 0x1F191, Hi: 0x1F19A, Stride: 1},
+		{Lo: 0x1F201, Hi: 0x1F201, Stride: 1},
+		{Lo: 0x1F21A, Hi: 0x1F21A, Stride: 1},
+		{Lo: 0x1F22F, Hi: 0x1F22F, Stride: 1},
+		{Lo: 0x1F232, Hi: 0x1F236, Stride: 1},
+		{Lo: 0x1F238, Hi: 0x1F23A, Stride: 1},
+		{Lo: 0x1F250, Hi: 0x1F251, Stride: 1},
+		{Lo: 0x1F300, Hi: 0x1F320, Stride: 1},
+		{Lo: 0x1F32D, Hi: 0x1F335, Stride: 1},
+		{Lo: 0x1F337, Hi: 0x1F37C, Stride: 1},
+		{Lo: 0x1F37E, Hi: 0x1F393, Stride: 1},
+		{Lo: 0x1F3A0, Hi: 0x1F3CA, Stride: 1},
+		{Lo: 0x1F3CF, Hi: 0x1F3D3, Stride: 1},
+		{Lo: 0x1F3E0, Hi: 0x1F3F0, Stride: 1},
+		{Lo: 0x1F3F4, Hi: 0x1F3F4, Stride: 1},
+		{Lo: 0x1F3F8, Hi: 0x1F43E, Stride: 1},
+		{Lo: 0x1F440, Hi: 0x1F440, Stride: 1},
+		{Lo: 0x1F442, Hi: 0x1F4FC, Stride: 1},
+		{Lo: 0x1F4FF, Hi: 0x1F53D, Stride: 1},
+		{Lo: 0x1F54B, Hi: 0x1F54E, Stride: 1},
+		{Lo: 0x1F550, Hi: 0x1F567, Stride: 1},
+		{Lo: 0x1F57A, Hi: 0x1F57A, Stride: 1},
+		{Lo: 0x1F595, Hi: 0x1F596, Stride: 1},
+		{Lo: 0x1F5A4, Hi: 0x1F5A4, Stride: 1},
+		{Lo: 0x1F5FB, Hi: 0x1F64F, Stride: 1},
+		{Lo: 0x1F680, Hi: 0x1F6C5, Stride: 1},
+		{Lo: 0x1F6CC, Hi: 0x1F6CC, Stride: 1},
+		{Lo: 0x1F6D0, Hi: 0x1F6D2, Stride: 1},
+		{Lo: 0x1F6D5, Hi: 0x1F6D7, Stride: 1},
+		{Lo: 0x1F6DC, Hi: 0x1F6DF, Stride: 1},
+		{Lo: 0x1F6EB, Hi: 0x1F6EC, Stride: 1},
+		{Lo: 0x1F6F4, Hi: 0x1F6FC, Stride: 1},
+		{Lo: 0x1F7E0, Hi: 0x1F7EB, Stride: 1},
+		{Lo: 0x1F7F0, Hi: 0x1F7F0, Stride: 1},
+		{Lo: 0x1F90C, Hi: 0x1F93A, Stride: 1},
+		{Lo: 0x1F93C, Hi: 0x1F945, Stride: 1},
+		{Lo: 0x1F947, Hi: 0x1F9FF, Stride: 1},
+		{Lo: 0x1FA70, Hi: 0x1FA7C, Stride: 1},
+		{Lo: 0x1FA80, Hi: 0x1FA89, Stride: 1},
+		{Lo: 0x1FA8F, Hi: 0x1FAC6, Stride: 1},
+		{Lo: 0x1FACE, Hi: 0x1FADC, Stride: 1},
+		{Lo: 0x1FADF, Hi: 0x1FAE9, Stride: 1},
+		{Lo: 0x1FAF0, Hi: 0x1FAF8, Stride: 1},
+	},
+}
+
+// VariationSelector - Giấu dữ liệu bằng các variation selector VS1–VS256 gắn sau emoji
+// hoặc ký tự. Mỗi selector mang đúng 1 byte: byte b < 16 là U+FE00+b,
+// còn lại là U+E0100+(b-16). Các selector không hiển thị nên cả payload nằm
+// trong một glyph nhìn thấy được.
+type VariationSelector struct {
+	// Spread - Rải payload đều lên mọi emoji trong văn bản thay vì gắn vào emoji đầu tiên
+	Spread bool
+	// BytesPerHost - Số byte tối đa gắn vào một emoji/ký tự
+	BytesPerHost int
+}
+
+// NewVariationSelector - Tạo phương pháp variation selector với cấu hình mặc định
+func NewVariationSelector() *VariationSelector {
+	return &VariationSelector{BytesPerHost: DefaultVariationBytesPerHost}
+}
+
+// ID - ID phương pháp trong header container
+func (v *VariationSelector) ID() uint8 {
+	return methodIDVariationSelector
+}
+
+// Name - Tên phương pháp
+func (v *VariationSelector) Name() string {
+	return MethodVariationSelector
+}
+
+// Capabilities - Đặc tính của phương pháp variation selector
+func (v *VariationSelector) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: true,
+		BitsPerChar:           8,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong văn bản phủ
+func (v *VariationSelector) Capacity(cover string) int {
+	_, hosts, _ := scanVariationSelectors(cover)
+	if len(hosts) == 0 {
+		return 0
+	}
+	if !v.Spread {
+		return v.bytesPerHost() * 8
+	}
+	return len(hosts) * v.bytesPerHost() * 8
+}
+
+// Embed - Gắn data vào emoji đầu tiên, hoặc rải đều lên mọi emoji nếu bật Spread.
+// Các selector mang dữ liệu có sẵn trong cover bị xóa trước khi giấu.
+func (v *VariationSelector) Embed(cover string, data []byte) (string, error) {
+	clean, hosts, _ := scanVariationSelectors(cover)
+	if len(hosts) == 0 {
+		return "", ErrEmptyCover
+	}
+	if len(data)*8 > v.Capacity(cover) {
+		return "", ErrCoverTooSmall
+	}
+
+	perHost := len(data)
+	if v.Spread {
+		perHost = (len(data) + len(hosts) - 1) / len(hosts)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(clean) + len(data)*4 + len(hosts)*3)
+	prev := 0
+	for _, host := range hosts {
+		sb.WriteString(clean[prev:host.end])
+		prev = host.end
+		if len(data) == 0 {
+			continue
+		}
+		n := perHost
+		if n > len(data) {
+			n = len(data)
+		}
+		// Selector đầu tiên có thể là FE0E/FE0F nên cần một selector trình bày
+		// đứng trước để không bị đọc nhầm thành một phần của emoji. Selector này giữ
+		// đúng cách hiển thị mặc định của ký tự để văn bản nhìn không đổi.
+		if host.presentation != 0 {
+			sb.WriteRune(host.presentation)
+		}
+		for _, b := range data[:n] {
+			sb.WriteRune(variationSelectorRune(b))
+		}
+		data = data[n:]
+	}
+	sb.WriteString(clean[prev:])
+	return sb.String(), nil
+}
+
+// Extract - Đọc các selector mang dữ liệu theo thứ tự xuất hiện
+func (v *VariationSelector) Extract(text string) ([]byte, error) {
+	_, _, data := scanVariationSelectors(text)
+	return data, nil
+}
+
+func (v *VariationSelector) bytesPerHost() int {
+	if v.BytesPerHost <= 0 {
+		return DefaultVariationBytesPerHost
+	}
+	return v.BytesPerHost
+}
+
+// variationHost - Vị trí (byte, trong văn bản đã bỏ selector dữ liệu) ngay sau một cụm
+// emoji. presentation là selector trình bày cần chèn trước dữ liệu nếu cụm kết thúc bằng
+// ký tự gốc chưa có selector: FE0F cho ký tự mặc định là emoji, FE0E cho ký tự mặc định là
+// chữ (©, ™, ↔...); 0 nếu không cần.
+type variationHost struct {
+	end          int
+	presentation rune
+}
+
+// scanVariationSelectors - Tách văn bản thành văn bản sạch, các vị trí gắn dữ liệu
+// và các byte đọc được từ selector. Selector trình bày (FE0E/FE0F) ngay sau emoji
+// thuộc về emoji và không được tính là dữ liệu.
+// Nếu văn bản không có emoji thì ký tự nhìn thấy đầu tiên được dùng làm host.
+func scanVariationSelectors(text string) (string, []variationHost, []byte) {
+	var sb strings.Builder
+	var hosts []variationHost
+	var data []byte
+	firstVisible := -1
+
+	for i := 0; i < len(text); {
+		r, n := utf8.DecodeRuneInString(text[i:])
+		if b, ok := variationSelectorByte(r); ok {
+			data = append(data, b)
+			i += n
+			continue
+		}
+		if isEmojiStart(text[i:]) {
+			end, base := emojiClusterEnd(text, i)
+			sb.WriteString(text[i:end])
+			host := variationHost{end: sb.Len()}
+			if base != 0 {
+				host.presentation = textPresentation
+				if unicode.Is(emojiPresentationBases, base) {
+					host.presentation = emojiPresentation
+				}
+			}
+			hosts = append(hosts, host)
+			i = end
+			continue
+		}
+		sb.WriteString(text[i : i+n])
+		if firstVisible < 0 && !unicode.IsSpace(r) && unicode.IsGraphic(r) {
+			firstVisible = sb.Len()
+		}
+		i += n
+	}
+
+	if len(hosts) == 0 && firstVisible >= 0 {
+		hosts = append(hosts, variationHost{end: firstVisible})
+	}
+	return sb.String(), hosts, data
+}
+
+// isEmojiStart - Kiểm tra text có bắt đầu bằng một cụm emoji không
+func isEmojiStart(text string) bool {
+	r, n := utf8.DecodeRuneInString(text)
+	switch {
+	case unicode.Is(emojiBases, r), isRegionalIndicator(r):
+		return true
+	case isKeycapBase(r):
+		next, m := utf8.DecodeRuneInString(text[n:])
+		if next == emojiPresentation {
+			next, _ = utf8.DecodeRuneInString(text[n+m:])
+		}
+		return next == combiningKeycap
+	}
+	return false
+}
+
+// emojiClusterEnd - Tìm vị trí kết thúc của cụm emoji bắt đầu tại start, gồm selector
+// trình bày, skin tone, tag (cờ vùng) và các emoji nối bằng ZWJ. Trả về thêm ký tự gốc
+// cuối cụm nếu nó chưa có selector trình bày, 0 nếu đã có.
+func emojiClusterEnd(text string, start int) (int, rune) {
+	i := start
+	for {
+		r, n := utf8.DecodeRuneInString(text[i:])
+		i += n
+		var bare rune
+		switch {
+		case isRegionalIndicator(r):
+			if next, m := utf8.DecodeRuneInString(text[i:]); isRegionalIndicator(next) {
+				i += m
+			}
+		case isKeycapBase(r):
+			if next, m := utf8.DecodeRuneInString(text[i:]); next == emojiPresentation {
+				i += m
+			}
+			_, m := utf8.DecodeRuneInString(text[i:])
+			i += m
+		default:
+			bare = r
+			if next, m := utf8.DecodeRuneInString(text[i:]); next == emojiPresentation || next == textPresentation {
+				i += m
+				bare = 0
+			}
+			if next, m := utf8.DecodeRuneInString(text[i:]); isSkinToneModifier(next) {
+				i += m
+				bare = 0
+			}
+			for {
+				next, m := utf8.DecodeRuneInString(text[i:])
+				if next < 0xE0020 || next > 0xE007F {
+					break
+				}
+				i += m
+				bare = 0
+			}
+		}
+
+		// Nối tiếp nếu có ZWJ theo sau là một emoji khác
+		next, m := utf8.DecodeRuneInString(text[i:])
+		if next != emojiJoiner {
+			return i, bare
+		}
+		after, _ := utf8.DecodeRuneInString(text[i+m:])
+		if !unicode.Is(emojiBases, after) {
+			return i, bare
+		}
+		i += m
+	}
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isSkinToneModifier(r rune) bool {
+	return r >= 0x1F3FB && r <= 0x1F3FF
+}
+
+func isKeycapBase(r rune) bool {
+	return r == '#' || r == '*' || (r >= '0' && r <= '9')
+}
+
+// variationSelectorRune - Selector biểu diễn một byte
+func variationSelectorRune(b byte) rune {
+	if b < 16 {
+		return 0xFE00 + rune(b)
+	}
+	return 0xE0100 + rune(b-16)
+}
+
+// variationSelectorByte - Byte được biểu diễn bởi một selector
+func variationSelectorByte(r rune) (byte, bool) {
+	switch {
+	case r >= 0xFE00 && r <= 0xFE0F:
+		return byte(r - 0xFE00), true
+	case r >= 0xE0100 && r <= 0xE01EF:
+		return byte(r-0xE0100) + 16, true
+	}
+	return 0, false
+}
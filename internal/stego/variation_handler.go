@@ -0,0 +1,85 @@
+package stego
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Các chế độ gắn dữ liệu của phương pháp variation selector
+const (
+	VariationModeSingle = "single"
+	VariationModeSpread = "spread"
+)
+
+// VariationEmbedRequest - Request body cho giấu tin bằng variation selector
+type VariationEmbedRequest struct {
+	EmbedFields
+	// Mode - "single" gắn cả payload vào emoji đầu tiên, "spread" rải đều lên mọi emoji
+	Mode         string `json:"mode" binding:"omitempty,oneof=single spread"`
+	BytesPerHost int    `json:"bytes_per_host" binding:"omitempty,gte=1,lte=65536"`
+	// Platform - Nền tảng sẽ nhận văn bản, dùng để cảnh báo nếu selector bị xóa
+	Platform string `json:"platform"`
+}
+
+// VariationExtractRequest - Request body cho trích xuất tin từ variation selector
+type VariationExtractRequest struct {
+	ExtractFields
+	Platform string `json:"platform"`
+}
+
+// ListPlatforms - Danh sách nền tảng có hành vi đã biết. Nền tảng không có trong danh sách
+// là chưa rõ hành vi.
+func (h *Handler) ListPlatforms(c *gin.Context) {
+	utils.RespondWithSuccess(c, http.StatusOK, "Platforms retrieved successfully", h.service.Platforms())
+}
+
+// VariationEmbed - Giấu message bằng variation selector gắn sau emoji
+func (h *Handler) VariationEmbed(c *gin.Context) {
+	var req VariationEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method := NewVariationSelector()
+	method.Spread = req.Mode == VariationModeSpread
+	if req.BytesPerHost > 0 {
+		method.BytesPerHost = req.BytesPerHost
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodVariationSelector))
+	var warnings []string
+	if h.stripsVariationSelectors(req.Platform) {
+		warnings = append(warnings, fmt.Sprintf("platform %q is known to strip variation selectors, the hidden data will not survive", req.Platform))
+	} else if _, ok := h.service.Platform(req.Platform); req.Platform != "" && !ok {
+		warnings = append(warnings, fmt.Sprintf("platform %q is unknown, whether it keeps variation selectors cannot be checked", req.Platform))
+	}
+	respondEmbedded(c, text, err, warnings...)
+}
+
+// VariationExtract - Trích xuất message từ variation selector
+func (h *Handler) VariationExtract(c *gin.Context) {
+	var req VariationExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	result, err := h.service.ExtractWithMethod(NewVariationSelector(), req.Text, req.options(MethodVariationSelector))
+	if errors.Is(err, ErrNoHiddenData) && h.stripsVariationSelectors(req.Platform) {
+		err = fmt.Errorf("%w (platform %q is known to strip variation selectors)", err, req.Platform)
+	}
+	respondExtracted(c, result, err)
+}
+
+// stripsVariationSelectors - Kiểm tra nền tảng có được biết là xóa variation selector không
+func (h *Handler) stripsVariationSelectors(name string) bool {
+	if name == "" {
+		return false
+	}
+	platform, ok := h.service.Platform(name)
+	return ok && platform.StripsVariationSelectors
+}
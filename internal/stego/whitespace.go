@@ -0,0 +1,144 @@
+package stego
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+)
+
+// Tên và ID của phương pháp khoảng trắng cuối dòng
+const (
+	MethodWhitespace   = "whitespace"
+	methodIDWhitespace = 2
+)
+
+// DefaultMaxLineLength - Độ dài dòng tối đa (tính cả khoảng trắng thêm vào)
+const DefaultMaxLineLength = 80
+
+// Whitespace - Giấu dữ liệu bằng dấu cách và tab ở cuối dòng theo kiểu công cụ SNOW.
+// Mỗi ký tự cuối dòng mang 1 bit: dấu cách là 0, tab là 1.
+// Phù hợp với file .txt, log, mã nguồn; hỗ trợ cả xuống dòng LF và CRLF.
+type Whitespace struct {
+	// MaxLineLength - Số ký tự tối đa của một dòng sau khi giấu tin
+	MaxLineLength int
+}
+
+// NewWhitespace - Tạo phương pháp khoảng trắng với cấu hình mặc định
+func NewWhitespace() *Whitespace {
+	return &Whitespace{MaxLineLength: DefaultMaxLineLength}
+}
+
+// ID - ID phương pháp trong header container
+func (w *Whitespace) ID() uint8 {
+	return methodIDWhitespace
+}
+
+// Name - Tên phương pháp
+func (w *Whitespace) Name() string {
+	return MethodWhitespace
+}
+
+// Capabilities - Đặc tính của phương pháp khoảng trắng
+func (w *Whitespace) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          false,
+		SurvivesNormalization: true,
+		BitsPerChar:           1,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong văn bản phủ
+func (w *Whitespace) Capacity(cover string) int {
+	bits := 0
+	for _, line := range splitLines(cover) {
+		bits += w.lineSlots(line.content)
+	}
+	return bits
+}
+
+// Embed - Giấu data vào cuối các dòng của cover
+func (w *Whitespace) Embed(cover string, data []byte) (string, error) {
+	if cover == "" {
+		return "", ErrEmptyCover
+	}
+	if len(data)*8 > w.Capacity(cover) {
+		return "", ErrCoverTooSmall
+	}
+
+	bits := bitutil.BytesToBits(data)
+	var sb strings.Builder
+	for _, line := range splitLines(cover) {
+		content := strings.TrimRight(line.content, " \t")
+		sb.WriteString(content)
+		n := w.lineSlots(line.content)
+		if n > len(bits) {
+			n = len(bits)
+		}
+		for _, bit := range bits[:n] {
+			if bit == 1 {
+				sb.WriteByte('\t')
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		bits = bits[n:]
+		sb.WriteString(line.ending)
+	}
+	return sb.String(), nil
+}
+
+// Extract - Đọc dữ liệu từ khoảng trắng cuối các dòng
+func (w *Whitespace) Extract(text string) ([]byte, error) {
+	var bits []byte
+	for _, line := range splitLines(text) {
+		content := strings.TrimRight(line.content, " \t")
+		for _, c := range line.content[len(content):] {
+			if c == '\t' {
+				bits = append(bits, 1)
+			} else {
+				bits = append(bits, 0)
+			}
+		}
+	}
+	return bitutil.BitsToBytes(bits), nil
+}
+
+// lineSlots - Số ký tự khoảng trắng có thể thêm vào cuối dòng
+func (w *Whitespace) lineSlots(content string) int {
+	maxLen := w.MaxLineLength
+	if maxLen <= 0 {
+		maxLen = DefaultMaxLineLength
+	}
+	n := maxLen - utf8.RuneCountInString(strings.TrimRight(content, " \t"))
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// textLine - Một dòng văn bản và ký tự xuống dòng đi kèm ("\n", "\r\n" hoặc rỗng)
+type textLine struct {
+	content string
+	ending  string
+}
+
+// splitLines - Tách văn bản thành các dòng, giữ nguyên kiểu xuống dòng
+func splitLines(text string) []textLine {
+	var lines []textLine
+	for text != "" {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			lines = append(lines, textLine{content: text})
+			break
+		}
+		line := textLine{content: text[:i], ending: "\n"}
+		if strings.HasSuffix(line.content, "\r") {
+			line.content = line.content[:len(line.content)-1]
+			line.ending = "\r\n"
+		}
+		lines = append(lines, line)
+		text = text[i+1:]
+	}
+	return lines
+}
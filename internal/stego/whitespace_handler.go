@@ -0,0 +1,40 @@
+package stego
+
+import (
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// WhitespaceEmbedRequest - Request body cho giấu tin bằng khoảng trắng cuối dòng
+type WhitespaceEmbedRequest struct {
+	EmbedFields
+	MaxLineLength int `json:"max_line_length" binding:"omitempty,gte=8,lte=1000"`
+}
+
+// WhitespaceEmbed - Giấu message vào khoảng trắng cuối dòng của văn bản phủ
+func (h *Handler) WhitespaceEmbed(c *gin.Context) {
+	var req WhitespaceEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method := NewWhitespace()
+	if req.MaxLineLength > 0 {
+		method.MaxLineLength = req.MaxLineLength
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodWhitespace))
+	respondEmbedded(c, text, err)
+}
+
+// WhitespaceExtract - Trích xuất message từ khoảng trắng cuối dòng
+func (h *Handler) WhitespaceExtract(c *gin.Context) {
+	var req ExtractFields
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	result, err := h.service.ExtractWithMethod(NewWhitespace(), req.Text, req.options(MethodWhitespace))
+	respondExtracted(c, result, err)
+}
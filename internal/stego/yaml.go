@@ -0,0 +1,432 @@
+package stego
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/baolamabcd13/datahiding-text-app/internal/bitutil"
+	"gopkg.in/yaml.v3"
+)
+
+// Tên và ID của phương pháp giấu tin trong tài liệu YAML
+const (
+	MethodYAML   = "yaml"
+	methodIDYAML = 17
+)
+
+// Tên các lớp vật mang của phương pháp YAML
+const (
+	YAMLKeyOrder = "key-order"
+	YAMLStyle    = "style"
+	YAMLQuotes   = "quotes"
+)
+
+// yamlIndent - Độ rộng thụt lề khi ghi lại tài liệu YAML
+const yamlIndent = 2
+
+// ErrInvalidYAML - Không phân tích được văn bản phủ thành tài liệu YAML
+var ErrInvalidYAML = errors.New("cover is not a valid YAML document")
+
+// YAMLClasses - Các lớp vật mang được bật. Mọi lớp đều chỉ đổi cách viết, tài liệu giải mã
+// ra cùng một giá trị.
+type YAMLClasses struct {
+	// KeyOrder - Thứ tự các khóa trong mapping, floor(log2(n!)) bit cho n khóa
+	KeyOrder bool
+	// Style - Mapping/sequence chỉ chứa giá trị đơn viết dạng block (0) hoặc flow (1)
+	Style bool
+	// Quotes - Chuỗi viết không nháy (0) hoặc nháy kép (1); chuỗi bắt buộc có nháy thì
+	// nháy đơn (0) hoặc nháy kép (1)
+	Quotes bool
+}
+
+// AllYAMLClasses - Bật tất cả các lớp vật mang
+var AllYAMLClasses = YAMLClasses{KeyOrder: true, Style: true, Quotes: true}
+
+// ParseYAMLClasses - Chuyển danh sách tên lớp thành YAMLClasses, rỗng là bật tất cả
+func ParseYAMLClasses(names []string) (YAMLClasses, error) {
+	if len(names) == 0 {
+		return AllYAMLClasses, nil
+	}
+	var classes YAMLClasses
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case YAMLKeyOrder:
+			classes.KeyOrder = true
+		case YAMLStyle:
+			classes.Style = true
+		case YAMLQuotes:
+			classes.Quotes = true
+		default:
+			return classes, fmt.Errorf("unknown yaml class %q", name)
+		}
+	}
+	return classes, nil
+}
+
+// YAML - Giấu dữ liệu trong tài liệu YAML bằng thứ tự khóa, kiểu block/flow và kiểu nháy.
+// Tài liệu được ghi lại bằng yaml.v3 (giữ chú thích) rồi giải mã và so với văn bản phủ
+// trước khi trả về.
+type YAML struct {
+	Classes YAMLClasses
+}
+
+// NewYAML - Tạo phương pháp YAML với các lớp vật mang đã chọn
+func NewYAML(classes YAMLClasses) *YAML {
+	return &YAML{Classes: classes}
+}
+
+// ID - ID phương pháp trong header container
+func (y *YAML) ID() uint8 {
+	return methodIDYAML
+}
+
+// Name - Tên phương pháp
+func (y *YAML) Name() string {
+	return MethodYAML
+}
+
+// Capabilities - Đặc tính của phương pháp YAML.
+// Thụt lề là một phần cấu trúc của YAML nên cắt khoảng trắng đầu dòng làm hỏng tài liệu.
+func (y *YAML) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          false,
+		SurvivesNormalization: true,
+		Structured:            true,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong tài liệu, 0 nếu không phải YAML hợp lệ
+func (y *YAML) Capacity(cover string) int {
+	docs, err := parseYAML(cover)
+	if err != nil {
+		return 0
+	}
+	return slotBits(y.slots(docs))
+}
+
+// Embed - Viết lại các vật mang theo từng nhóm bit của data rồi ghi lại tài liệu
+func (y *YAML) Embed(cover string, data []byte) (string, error) {
+	if strings.TrimSpace(cover) == "" {
+		return "", ErrEmptyCover
+	}
+	docs, err := parseYAML(cover)
+	if err != nil {
+		return "", err
+	}
+	slots := y.slots(docs)
+	bits := bitutil.BytesToBits(data)
+	if len(bits) > slotBits(slots) {
+		return "", ErrCoverTooSmall
+	}
+	writeSlots(slots, bits)
+
+	text, err := encodeYAML(docs)
+	if err != nil || !sameYAMLValue(cover, text) {
+		return "", ErrDocumentValueChanged
+	}
+	// Đọc lại để chắc chắn yaml.v3 giữ đúng kiểu viết đã chọn
+	check, err := parseYAML(text)
+	if err != nil || !hasBitsPrefix(readSlots(y.slots(check)), bits) {
+		return "", ErrDocumentValueChanged
+	}
+	return text, nil
+}
+
+// Extract - Đọc bit từ các vật mang theo thứ tự trong tài liệu, tài liệu YAML lỗi không
+// mang dữ liệu
+func (y *YAML) Extract(text string) ([]byte, error) {
+	docs, err := parseYAML(text)
+	if err != nil {
+		return nil, ErrNoHiddenData
+	}
+	return bitutil.BitsToBytes(readSlots(y.slots(docs))), nil
+}
+
+// slots - Các vật mang theo thứ tự duyệt cây. Cặp khóa/giá trị được duyệt theo thứ tự chuẩn
+// để không phụ thuộc vào thứ tự khóa đang mang.
+func (y *YAML) slots(docs []*yaml.Node) []structSlot {
+	// Đổi thứ tự khóa có thể đặt alias trước anchor của nó nên tài liệu có anchor không
+	// mang tin bằng thứ tự khóa
+	reorder := y.Classes.KeyOrder
+	for _, doc := range docs {
+		if hasYAMLAnchors(doc) {
+			reorder = false
+		}
+	}
+
+	var slots []structSlot
+	var walk func(n *yaml.Node, flow bool)
+	walk = func(n *yaml.Node, flow bool) {
+		switch n.Kind {
+		case yaml.DocumentNode:
+			for _, child := range n.Content {
+				walk(child, false)
+			}
+		case yaml.MappingNode:
+			if bits := yamlOrderBits(n); reorder && bits > 0 {
+				slots = append(slots, yamlOrderSlot(n, bits))
+			}
+			if y.Classes.Style && !flow && yamlStyleSlot(n) {
+				slots = append(slots, yamlFlowSlot(n))
+			}
+			flow = flow || n.Style&yaml.FlowStyle != 0
+			canonical := yamlCanonical(n)
+			for _, i := range canonical {
+				walk(n.Content[2*i], flow)
+				walk(n.Content[2*i+1], flow)
+			}
+		case yaml.SequenceNode:
+			if y.Classes.Style && !flow && yamlStyleSlot(n) {
+				slots = append(slots, yamlFlowSlot(n))
+			}
+			flow = flow || n.Style&yaml.FlowStyle != 0
+			for _, child := range n.Content {
+				walk(child, flow)
+			}
+		case yaml.ScalarNode:
+			if slot, ok := yamlQuoteSlot(n); y.Classes.Quotes && ok {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	for _, doc := range docs {
+		walk(doc, false)
+	}
+	return slots
+}
+
+// parseYAML - Phân tích tất cả các tài liệu trong văn bản
+func parseYAML(text string) ([]*yaml.Node, error) {
+	dec := yaml.NewDecoder(strings.NewReader(text))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidYAML, err)
+		}
+		docs = append(docs, &doc)
+	}
+	if len(docs) == 0 {
+		return nil, ErrInvalidYAML
+	}
+	return docs, nil
+}
+
+// encodeYAML - Ghi lại các tài liệu bằng yaml.v3
+func encodeYAML(docs []*yaml.Node) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(yamlIndent)
+	for _, doc := range docs {
+		untagYAMLMergeKeys(doc)
+		if err := enc.Encode(doc); err != nil {
+			return "", err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// sameYAMLValue - Hai văn bản giải mã ra cùng các giá trị
+func sameYAMLValue(a, b string) bool {
+	decode := func(text string) ([]interface{}, bool) {
+		dec := yaml.NewDecoder(strings.NewReader(text))
+		var values []interface{}
+		for {
+			var v interface{}
+			err := dec.Decode(&v)
+			if err == io.EOF {
+				return values, true
+			}
+			if err != nil {
+				return nil, false
+			}
+			values = append(values, v)
+		}
+	}
+	va, okA := decode(a)
+	vb, okB := decode(b)
+	return okA && okB && reflect.DeepEqual(va, vb)
+}
+
+// untagYAMLMergeKeys - yaml.v3 ghi khóa gộp kèm tag (!!merge <<), bỏ tag để giữ cách viết <<
+func untagYAMLMergeKeys(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.Value == "<<" && n.ShortTag() == "!!merge" && n.Style == 0 {
+		n.Tag = ""
+	}
+	for _, child := range n.Content {
+		untagYAMLMergeKeys(child)
+	}
+}
+
+func hasYAMLAnchors(n *yaml.Node) bool {
+	if n.Anchor != "" || n.Kind == yaml.AliasNode {
+		return true
+	}
+	for _, child := range n.Content {
+		if hasYAMLAnchors(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlCanonical - Chỉ số các cặp khóa/giá trị sắp theo khóa, là thứ tự ứng với hoán vị đồng nhất
+func yamlCanonical(n *yaml.Node) []int {
+	indices := make([]int, len(n.Content)/2)
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		return n.Content[2*indices[a]].Value < n.Content[2*indices[b]].Value
+	})
+	return indices
+}
+
+// yamlOrderBits - Số bit mang bởi thứ tự khóa. Chỉ dùng mapping có khóa là giá trị đơn,
+// không trùng nhau và không có khóa gộp <<.
+func yamlOrderBits(n *yaml.Node) int {
+	seen := make(map[string]bool, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i]
+		if key.Kind != yaml.ScalarNode || key.Value == "<<" || seen[key.Value] {
+			return 0
+		}
+		seen[key.Value] = true
+	}
+	return bitutil.PermutationBits(len(n.Content) / 2)
+}
+
+func yamlOrderSlot(n *yaml.Node, bits int) structSlot {
+	canonical := yamlCanonical(n)
+	order := make([]int, len(canonical))
+	for rank, i := range canonical {
+		order[i] = rank
+	}
+	return structSlot{
+		bits:  bits,
+		value: bitutil.PermutationIndex(order),
+		write: func(value uint64) {
+			pairs := make([]*yaml.Node, 0, len(n.Content))
+			for _, rank := range bitutil.PermutationFromIndex(len(canonical), value) {
+				i := canonical[rank]
+				pairs = append(pairs, n.Content[2*i], n.Content[2*i+1])
+			}
+			n.Content = pairs
+		},
+	}
+}
+
+// yamlStyleSlot - Mapping/sequence khác rỗng chỉ chứa giá trị đơn không có chú thích.
+// Phần tử của collection dạng flow luôn là flow nên collection lồng nhau không được dùng.
+func yamlStyleSlot(n *yaml.Node) bool {
+	if len(n.Content) == 0 || n.LineComment != "" || n.FootComment != "" {
+		return false
+	}
+	for _, child := range n.Content {
+		if child.Kind != yaml.ScalarNode || strings.Contains(child.Value, "\n") ||
+			child.HeadComment != "" || child.LineComment != "" || child.FootComment != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func yamlFlowSlot(n *yaml.Node) structSlot {
+	var current uint64
+	if n.Style&yaml.FlowStyle != 0 {
+		current = 1
+	}
+	return structSlot{
+		bits:  1,
+		value: current,
+		write: func(value uint64) {
+			n.Style &^= yaml.FlowStyle
+			if value == 1 {
+				n.Style |= yaml.FlowStyle
+			}
+		},
+	}
+}
+
+// yamlQuoteSlot - Chuỗi một dòng có thể viết không nháy lẫn nháy kép thì mang bit không
+// nháy (0) hoặc có nháy (1). Chuỗi bắt buộc có nháy mang bit nháy đơn (0) hoặc nháy kép (1).
+// Kiểu viết có dùng được hay không do yaml.v3 quyết định nên được thử trước.
+func yamlQuoteSlot(n *yaml.Node) (structSlot, bool) {
+	const quoted = yaml.SingleQuotedStyle | yaml.DoubleQuotedStyle
+	if n.ShortTag() != "!!str" || n.Style&^quoted != 0 || n.Anchor != "" ||
+		strings.ContainsAny(n.Value, "\r\n") || !utf8.ValidString(n.Value) ||
+		!yamlKeepsStyle(n.Value, yaml.DoubleQuotedStyle) {
+		return structSlot{}, false
+	}
+
+	if yamlKeepsStyle(n.Value, 0) {
+		var current uint64
+		if n.Style&quoted != 0 {
+			current = 1
+		}
+		return structSlot{
+			bits:  1,
+			value: current,
+			write: func(value uint64) {
+				n.Style = 0
+				if value == 1 {
+					n.Style = yaml.DoubleQuotedStyle
+				}
+			},
+		}, true
+	}
+	if !yamlKeepsStyle(n.Value, yaml.SingleQuotedStyle) {
+		return structSlot{}, false
+	}
+	var current uint64
+	if n.Style&yaml.DoubleQuotedStyle != 0 {
+		current = 1
+	}
+	return structSlot{
+		bits:  1,
+		value: current,
+		write: func(value uint64) {
+			n.Style = yaml.SingleQuotedStyle
+			if value == 1 {
+				n.Style = yaml.DoubleQuotedStyle
+			}
+		},
+	}, true
+}
+
+// yamlKeepsStyle - yaml.v3 ghi chuỗi value đúng kiểu style (0 là không nháy) khi nằm trong
+// một sequence dạng flow, ngữ cảnh chặt nhất mà chuỗi có thể xuất hiện
+func yamlKeepsStyle(value string, style yaml.Style) bool {
+	seq := &yaml.Node{
+		Kind:  yaml.SequenceNode,
+		Style: yaml.FlowStyle,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: style},
+		},
+	}
+	out, err := yaml.Marshal(seq)
+	if err != nil || len(out) < 2 {
+		return false
+	}
+	switch out[1] {
+	case '\'':
+		return style == yaml.SingleQuotedStyle
+	case '"':
+		return style == yaml.DoubleQuotedStyle
+	default:
+		return style == 0
+	}
+}
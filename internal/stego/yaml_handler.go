@@ -0,0 +1,67 @@
+package stego
+
+import (
+	"github.com/baolamabcd13/datahiding-text-app/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// YAMLFields - Các lớp vật mang dùng cho request. Khi trích xuất phải dùng đúng các lớp
+// đã dùng khi giấu tin; để trống là bật tất cả.
+type YAMLFields struct {
+	Classes []string `json:"classes" binding:"omitempty,dive,oneof=key-order style quotes"`
+}
+
+// method - Tạo phương pháp theo các lớp trong request
+func (f *YAMLFields) method() (*YAML, error) {
+	classes, err := ParseYAMLClasses(f.Classes)
+	if err != nil {
+		return nil, err
+	}
+	return NewYAML(classes), nil
+}
+
+// YAMLEmbedRequest - Request body cho giấu tin trong tài liệu YAML
+type YAMLEmbedRequest struct {
+	EmbedFields
+	YAMLFields
+}
+
+// YAMLExtractRequest - Request body cho trích xuất tin từ tài liệu YAML
+type YAMLExtractRequest struct {
+	ExtractFields
+	YAMLFields
+}
+
+// YAMLEmbed - Giấu message vào tài liệu YAML mà không thay đổi giá trị giải mã
+func (h *Handler) YAMLEmbed(c *gin.Context) {
+	var req YAMLEmbedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	text, err := h.service.EmbedWithMethod(method, req.Cover, req.Message, req.options(MethodYAML))
+	respondEmbedded(c, text, err)
+}
+
+// YAMLExtract - Trích xuất message từ tài liệu YAML
+func (h *Handler) YAMLExtract(c *gin.Context) {
+	var req YAMLExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithValidationError(c, err)
+		return
+	}
+
+	method, err := req.method()
+	if err != nil {
+		respondWithStegoError(c, err)
+		return
+	}
+	result, err := h.service.ExtractWithMethod(method, req.Text, req.options(MethodYAML))
+	respondExtracted(c, result, err)
+}
@@ -0,0 +1,217 @@
+package stego
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Các ký tự zero-width dùng để mã hóa, mỗi ký tự mang 2 bit
+const (
+	zeroWidthSpace     = '\u200B' // 00
+	zeroWidthNonJoiner = '\u200C' // 01
+	zeroWidthJoiner    = '\u200D' // 10
+	wordJoiner         = '\u2060' // 11
+)
+
+// Tên và ID của phương pháp zero-width
+const (
+	MethodZeroWidth   = "zero-width"
+	methodIDZeroWidth = 1
+)
+
+// DefaultZeroWidthBytesPerGap - Số byte tối đa chèn vào sau mỗi từ
+const DefaultZeroWidthBytesPerGap = 8
+
+var zeroWidthAlphabet = [4]rune{zeroWidthSpace, zeroWidthNonJoiner, zeroWidthJoiner, wordJoiner}
+
+// ErrCoverTooSmall - Văn bản phủ không đủ chỗ cho dữ liệu
+var ErrCoverTooSmall = errors.New("cover text is too short for the payload")
+
+// ErrEmptyCover - Văn bản phủ rỗng
+var ErrEmptyCover = errors.New("cover text is empty")
+
+// ZeroWidth - Giấu dữ liệu bằng các ký tự zero-width chèn vào cuối mỗi từ
+type ZeroWidth struct {
+	// BytesPerGap - Số byte tối đa chèn vào sau mỗi từ
+	BytesPerGap int
+}
+
+// NewZeroWidth - Tạo phương pháp zero-width với cấu hình mặc định
+func NewZeroWidth() *ZeroWidth {
+	return &ZeroWidth{BytesPerGap: DefaultZeroWidthBytesPerGap}
+}
+
+// ID - ID phương pháp trong header container
+func (z *ZeroWidth) ID() uint8 {
+	return methodIDZeroWidth
+}
+
+// Name - Tên phương pháp
+func (z *ZeroWidth) Name() string {
+	return MethodZeroWidth
+}
+
+// Capabilities - Đặc tính của phương pháp zero-width
+func (z *ZeroWidth) Capabilities() Capabilities {
+	return Capabilities{
+		SurvivesTrim:          true,
+		SurvivesNormalization: true,
+		BitsPerChar:           2,
+	}
+}
+
+// Capacity - Số bit tối đa có thể giấu trong văn bản phủ
+func (z *ZeroWidth) Capacity(cover string) int {
+	return len(wordEnds(stripZeroWidth(cover))) * z.bytesPerGap() * 8
+}
+
+// Embed - Giấu data vào cover, trả về văn bản đã giấu tin
+func (z *ZeroWidth) Embed(cover string, data []byte) (string, error) {
+	runes := []rune(stripZeroWidth(cover))
+	ends := wordEnds(string(runes))
+	if len(ends) == 0 {
+		return "", ErrEmptyCover
+	}
+	perGap := z.bytesPerGap()
+	if len(data) > len(ends)*perGap {
+		return "", ErrCoverTooSmall
+	}
+
+	var sb strings.Builder
+	prev := 0
+	for _, end := range ends {
+		sb.WriteString(string(runes[prev:end]))
+		prev = end
+		if len(data) == 0 {
+			continue
+		}
+		n := perGap
+		if n > len(data) {
+			n = len(data)
+		}
+		for _, b := range data[:n] {
+			for shift := 6; shift >= 0; shift -= 2 {
+				sb.WriteRune(zeroWidthAlphabet[(b>>uint(shift))&0x03])
+			}
+		}
+		data = data[n:]
+	}
+	sb.WriteString(string(runes[prev:]))
+	return sb.String(), nil
+}
+
+// Extract - Lấy lại dữ liệu đã giấu trong văn bản.
+// Nhóm ký tự bị mất bớt (ví dụ khi đi qua ứng dụng chat) được đệm lại cho đủ độ dài
+// để lỗi chỉ nằm trong nhóm đó, giúp lớp ECC có thể sửa được.
+func (z *ZeroWidth) Extract(text string) ([]byte, error) {
+	groups := zeroWidthGroups(text)
+	groupLen := z.bytesPerGap() * 4
+
+	var data []byte
+	for i, group := range groups {
+		switch {
+		case i < len(groups)-1 && len(group) < groupLen:
+			group = padZeroWidth(group, groupLen)
+		case len(group)%4 != 0:
+			group = padZeroWidth(group, len(group)+4-len(group)%4)
+		}
+		for j := 0; j+4 <= len(group); j += 4 {
+			var b byte
+			for _, r := range group[j : j+4] {
+				b = b<<2 | zeroWidthValue(r)
+			}
+			data = append(data, b)
+		}
+	}
+	return data, nil
+}
+
+func padZeroWidth(group []rune, n int) []rune {
+	padded := make([]rune, n)
+	copy(padded, group)
+	for i := len(group); i < n; i++ {
+		padded[i] = zeroWidthSpace
+	}
+	return padded
+}
+
+func (z *ZeroWidth) bytesPerGap() int {
+	if z.BytesPerGap <= 0 {
+		return DefaultZeroWidthBytesPerGap
+	}
+	return z.BytesPerGap
+}
+
+// isZeroWidth - Kiểm tra ký tự có thuộc bảng mã zero-width không
+func isZeroWidth(r rune) bool {
+	return r == zeroWidthSpace || r == zeroWidthNonJoiner || r == zeroWidthJoiner || r == wordJoiner
+}
+
+func zeroWidthValue(r rune) byte {
+	for i, c := range zeroWidthAlphabet {
+		if c == r {
+			return byte(i)
+		}
+	}
+	return 0
+}
+
+// zeroWidthGroups - Tìm các chuỗi ký tự zero-width nằm ở cuối từ.
+// Chuỗi nằm giữa từ (ví dụ ZWJ trong emoji ghép) không được tính.
+func zeroWidthGroups(text string) [][]rune {
+	runes := []rune(text)
+	var groups [][]rune
+	for i := 0; i < len(runes); {
+		if !isZeroWidth(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isZeroWidth(runes[j]) {
+			j++
+		}
+		if j == len(runes) || unicode.IsSpace(runes[j]) {
+			groups = append(groups, runes[i:j])
+		}
+		i = j
+	}
+	return groups
+}
+
+// stripZeroWidth - Xóa các chuỗi zero-width có sẵn ở cuối từ để dữ liệu trích xuất được chính xác
+func stripZeroWidth(text string) string {
+	runes := []rune(text)
+	var sb strings.Builder
+	for i := 0; i < len(runes); {
+		if !isZeroWidth(runes[i]) {
+			sb.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isZeroWidth(runes[j]) {
+			j++
+		}
+		if j < len(runes) && !unicode.IsSpace(runes[j]) {
+			sb.WriteString(string(runes[i:j]))
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+// wordEnds - Vị trí (theo rune) ngay sau ký tự cuối cùng của mỗi từ
+func wordEnds(text string) []int {
+	runes := []rune(text)
+	var ends []int
+	for i := 1; i <= len(runes); i++ {
+		if unicode.IsSpace(runes[i-1]) {
+			continue
+		}
+		if i == len(runes) || unicode.IsSpace(runes[i]) {
+			ends = append(ends, i)
+		}
+	}
+	return ends
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/baolamabcd13/datahiding-text-app/internal/validation"
@@ -22,6 +23,19 @@ type ValidationErrorResponse struct {
 	Errors  map[string]string `json:"errors"`
 }
 
+// FieldErrors - Lỗi validation theo từng trường không đến từ validator
+type FieldErrors map[string]string
+
+// Error - Triển khai error interface
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for field, message := range e {
+		messages = append(messages, field+": "+message)
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
+
 // SuccessResponse - Cấu trúc response cho thành công
 type SuccessResponse struct {
 	Status  string      `json:"status"`
@@ -57,6 +71,15 @@ func RespondWithValidationError(c *gin.Context, err error) {
 		})
 		return
 	}
+
+	if fieldErrors, ok := err.(FieldErrors); ok {
+		c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Status:  "error",
+			Message: "Validation failed",
+			Errors:  fieldErrors,
+		})
+		return
+	}
 	
 	// If it's not a validation error, return a generic error
 	RespondWithError(c, http.StatusBadRequest, err.Error())